@@ -0,0 +1,129 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/db"
+	"github.com/emilianohg/anchorman/internal/models"
+	"github.com/emilianohg/anchorman/internal/repository"
+)
+
+// openDatabase opens the database and applies pending migrations.
+// Headless commands have no dashboard to prompt for migrations, so they run them directly.
+func openDatabase() (*sql.DB, error) {
+	database, err := db.OpenAndMigrate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return database, nil
+}
+
+// dateFlag parses a YYYY-MM-DD flag in the calendar's time zone. When endOfDay is set the
+// returned time is the last second of that day, so it can be used as an inclusive upper bound.
+func dateFlag(cmd *cobra.Command, name string, cal daterange.Calendar, endOfDay bool) (time.Time, error) {
+	value, _ := cmd.Flags().GetString(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := cal.ParseDate(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --%s: %s (expected YYYY-MM-DD)", name, value)
+	}
+
+	if endOfDay {
+		date = cal.EndOfDay(date)
+	}
+	return date, nil
+}
+
+// rangeFlags reads the --range preset or the --from/--to flags.
+// Both returned times are zero when none of them is set.
+func rangeFlags(cmd *cobra.Command, cal daterange.Calendar) (time.Time, time.Time, error) {
+	preset, _ := cmd.Flags().GetString("range")
+	if preset != "" {
+		if cmd.Flags().Changed("from") || cmd.Flags().Changed("to") {
+			return time.Time{}, time.Time{}, fmt.Errorf("--range cannot be combined with --from/--to")
+		}
+		p, err := daterange.ParsePreset(preset)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from, to := cal.Range(p)
+		return from, to, nil
+	}
+
+	from, err := dateFlag(cmd, "from", cal, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := dateFlag(cmd, "to", cal, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--to must not be before --from")
+	}
+	return from, to, nil
+}
+
+// rangeFlagUsage lists the accepted --range values
+func rangeFlagUsage() string {
+	var slugs []string
+	for p := daterange.Today; p < daterange.Custom; p++ {
+		slugs = append(slugs, p.Slug())
+	}
+	return "Preset date range instead of --from/--to: " + strings.Join(slugs, ", ")
+}
+
+// resolveCompany looks up a company by name, returning nil when name is empty
+func resolveCompany(database *sql.DB, name string) (*models.Company, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	company, err := repository.NewCompanyRepo(database).GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if company == nil {
+		return nil, fmt.Errorf("company not found: %s", name)
+	}
+	return company, nil
+}
+
+// resolveProject looks up a project by name, optionally within a company.
+// Returns nil when name is empty.
+func resolveProject(database *sql.DB, name string, company *models.Company) (*models.Project, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	projects, err := repository.NewProjectRepo(database).GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []models.Project
+	for _, p := range projects {
+		if company != nil && (p.CompanyID == nil || *p.CompanyID != company.ID) {
+			continue
+		}
+		matches = append(matches, p)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("project not found: %s", name)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("project name %q is ambiguous, use --company to narrow it down", name)
+	}
+}
@@ -180,9 +180,43 @@ func init() {
 	importCmd.Flags().StringP("branch", "b", "", "Specific branch (default: all branches)")
 	importCmd.Flags().BoolP("force", "f", false, "Re-ingest existing commits, mark as unprocessed, delete related tasks")
 
+	processCmd.Flags().String("from", "", "Only commits on or after this date (YYYY-MM-DD)")
+	processCmd.Flags().String("to", "", "Only commits on or before this date (YYYY-MM-DD)")
+	processCmd.Flags().String("range", "", rangeFlagUsage())
+	processCmd.Flags().StringP("project", "p", "", "Only process this project")
+	processCmd.Flags().StringP("company", "c", "", "Only process projects of this company")
+	processCmd.Flags().String("agent", "", "Agent to use (default: default_agent from config)")
+	processCmd.Flags().Int("concurrency", 0, "Projects to process in parallel (default: concurrency from config)")
+
+	reprocessCmd.Flags().String("from", "", "Only tasks on or after this date (YYYY-MM-DD)")
+	reprocessCmd.Flags().String("to", "", "Only tasks on or before this date (YYYY-MM-DD)")
+	reprocessCmd.Flags().String("range", "", rangeFlagUsage())
+	reprocessCmd.Flags().StringP("project", "p", "", "Only reprocess this project")
+	reprocessCmd.Flags().StringP("company", "c", "", "Only reprocess projects of this company")
+	reprocessCmd.Flags().String("agent", "", "Agent to use (default: default_agent from config)")
+	reprocessCmd.Flags().Int("concurrency", 0, "Projects to process in parallel (default: concurrency from config)")
+	reprocessCmd.Flags().Bool("dry-run", false, "Only list the tasks that would be replaced")
+	reprocessCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation")
+
+	reportCmd.Flags().StringP("company", "c", "", "Company to report on (required)")
+	reportCmd.Flags().String("from", "", "Start date, inclusive (YYYY-MM-DD)")
+	reportCmd.Flags().String("to", "", "End date, inclusive (YYYY-MM-DD)")
+	reportCmd.Flags().String("range", "", rangeFlagUsage())
+	reportCmd.Flags().BoolP("time", "t", false, "Include time estimates")
+	reportCmd.Flags().BoolP("authors", "a", false, "Include authors")
+	reportCmd.Flags().Bool("changes", false, "Include lines added and removed per project")
+	reportCmd.Flags().StringP("output", "o", "", "Output file, or - for stdout (default: reports_output)")
+	reportCmd.Flags().String("format", "", "Output format: markdown, html, csv, json or text (default: report_format from config)")
+	reportCmd.Flags().String("group-by", "project", "Task breakdown: project, project-day, project-week or day")
+	reportCmd.Flags().String("order", "", "Project order: name, hours or manual (default: report_order of the company, or name)")
+	reportCmd.Flags().String("template", "", "Template name in ~/.anchorman/templates or a template file path")
+
 	rootCmd.AddCommand(ingestCmd)
 	rootCmd.AddCommand(hooksCmd)
 	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(processCmd)
+	rootCmd.AddCommand(reprocessCmd)
+	rootCmd.AddCommand(reportCmd)
 }
 
 func main() {
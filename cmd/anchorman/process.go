@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emilianohg/anchorman/internal/agent"
+	"github.com/emilianohg/anchorman/internal/config"
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/db"
+	"github.com/emilianohg/anchorman/internal/processor"
+)
+
+var processCmd = &cobra.Command{
+	Use:   "process",
+	Short: "Summarize unprocessed commits into tasks without the TUI",
+	Long: `Summarize unprocessed commits into tasks using the configured AI agent.
+
+Examples:
+  anchorman process                                # All unprocessed commits
+  anchorman process --from 2025-01-01 --to 2025-01-31
+  anchorman process --range last-month
+  anchorman process --company Acme                 # Only Acme's projects
+  anchorman process --project api --agent claude`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runProcess(cmd); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runProcess(cmd *cobra.Command) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	opts, err := processOptions(cmd, database, daterange.NewCalendar(cfg))
+	if err != nil {
+		return err
+	}
+
+	agentName, ag, err := agentFlag(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	prompts, err := agent.LoadPromptTemplates(cfg)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Processing with %s...\n", agentName)
+
+	// Ctrl+C stops after the current agent call; tasks saved so far are kept
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency <= 0 {
+		concurrency = cfg.Concurrency
+	}
+
+	proc := processor.New(database, ag, daterange.NewCalendar(cfg)).
+		WithConcurrency(concurrency).
+		WithChunking(processor.ChunkingFromConfig(cfg)).
+		WithPrompts(prompts)
+	proc.OnProgress(printProgress)
+
+	result, err := proc.Process(ctx, opts)
+	return finishProcessing(result, err)
+}
+
+// processOptions reads the date range and project/company flags shared by
+// process and reprocess
+func processOptions(cmd *cobra.Command, database *sql.DB, cal daterange.Calendar) (processor.Options, error) {
+	opts := processor.Options{}
+	var err error
+	if opts.From, opts.To, err = rangeFlags(cmd, cal); err != nil {
+		return opts, err
+	}
+
+	companyName, _ := cmd.Flags().GetString("company")
+	company, err := resolveCompany(database, companyName)
+	if err != nil {
+		return opts, err
+	}
+	if company != nil {
+		opts.CompanyID = &company.ID
+	}
+
+	projectName, _ := cmd.Flags().GetString("project")
+	project, err := resolveProject(database, projectName, company)
+	if err != nil {
+		return opts, err
+	}
+	if project != nil {
+		opts.ProjectID = &project.ID
+	}
+
+	return opts, nil
+}
+
+// agentFlag creates the agent named by --agent, or the default agent
+func agentFlag(cmd *cobra.Command, cfg *config.Config) (string, agent.Agent, error) {
+	agentName, _ := cmd.Flags().GetString("agent")
+	if agentName == "" {
+		agentName = cfg.DefaultAgent
+	}
+	ag, err := agent.New(agentName, cfg)
+	if err != nil {
+		return "", nil, err
+	}
+	return agentName, ag, nil
+}
+
+// printProgress prints a line for each project that finished processing
+func printProgress(p processor.Progress) {
+	if !p.Done {
+		return
+	}
+	if p.Project.Err != nil {
+		fmt.Printf("  [%d/%d] %s: FAILED (%d commits left unprocessed): %v\n",
+			p.Finished, p.Total, p.Project.ProjectName, len(p.Project.FailedCommits), p.Project.Err)
+		return
+	}
+	fmt.Printf("  [%d/%d] %s: %d commits -> %d tasks\n",
+		p.Finished, p.Total, p.Project.ProjectName, p.Project.Commits, p.Project.TasksCreated)
+}
+
+// finishProcessing prints the outcome of a processing run and turns interruptions
+// and failed projects into an error
+func finishProcessing(result *processor.Result, err error) error {
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("interrupted after creating %d tasks; run 'anchorman process' to finish", result.TasksCreated)
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.OrphanCommits > 0 {
+		fmt.Printf("Skipped: %d commits from orphan repos\n", result.OrphanCommits)
+	}
+	fmt.Printf("Created %d tasks across %d projects\n", result.TasksCreated, len(result.Projects))
+
+	if failed := result.Failed(); len(failed) > 0 {
+		return fmt.Errorf("%d of %d projects failed; run 'anchorman process' to retry their %d commits",
+			len(failed), len(result.Projects), len(result.FailedCommits()))
+	}
+	return nil
+}
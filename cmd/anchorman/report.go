@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emilianohg/anchorman/internal/config"
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/db"
+	"github.com/emilianohg/anchorman/internal/locale"
+	"github.com/emilianohg/anchorman/internal/report"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a company report without the TUI",
+	Long: `Generate a report for a company over an arbitrary date range.
+
+By default the report is saved to reports_output with the same file name
+the TUI uses. Use -o to write to a specific file, or -o - for stdout.
+
+Examples:
+  anchorman report --company Acme --from 2025-01-01 --to 2025-01-31
+  anchorman report --company Acme --range last-month
+  anchorman report --company Acme --from 2025-01-01 --to 2025-01-31 --time --authors
+  anchorman report --company Acme --from 2025-01-01 --to 2025-01-31 --format csv -o hours.csv
+  anchorman report --company Acme --from 2025-01-01 --to 2025-01-31 --template client-summary
+  anchorman report --company Acme --from 2025-01-01 --to 2025-01-31 --time --group-by project-day
+  anchorman report --company Acme --from 2025-01-01 --to 2025-01-31 -o - | mail -s Report boss@acme.com`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runReport(cmd); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runReport(cmd *cobra.Command) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	companyName, _ := cmd.Flags().GetString("company")
+	company, err := resolveCompany(database, companyName)
+	if err != nil {
+		return err
+	}
+	if company == nil {
+		return fmt.Errorf("--company is required")
+	}
+
+	opts := report.Options{CompanyID: company.ID, Calendar: daterange.NewCalendar(cfg)}
+	if opts.From, opts.To, err = rangeFlags(cmd, opts.Calendar); err != nil {
+		return err
+	}
+	if opts.From.IsZero() || opts.To.IsZero() {
+		return fmt.Errorf("either --range or both --from and --to are required")
+	}
+	opts.ShowTime, _ = cmd.Flags().GetBool("time")
+	opts.ShowAuthors, _ = cmd.Flags().GetBool("authors")
+	opts.ShowChanges, _ = cmd.Flags().GetBool("changes")
+	order, _ := cmd.Flags().GetString("order")
+	if order == "" {
+		order = cfg.Company(company.Name).ReportOrder
+	}
+	if opts.Order, err = report.ParseOrder(order); err != nil {
+		return err
+	}
+	if opts.Locale, err = locale.Parse(cfg.Company(company.Name).Locale); err != nil {
+		return err
+	}
+	groupBy, _ := cmd.Flags().GetString("group-by")
+	if opts.GroupBy, err = report.ParseGroupBy(groupBy); err != nil {
+		return err
+	}
+
+	format, _ := cmd.Flags().GetString("format")
+	templateName, _ := cmd.Flags().GetString("template")
+	switch {
+	case templateName != "":
+		format = report.TemplateFormat(templateName)
+	case format == "":
+		format = cfg.ReportFormat
+		if t := cfg.Company(company.Name).ReportTemplate; t != "" {
+			format = report.TemplateFormat(t)
+		}
+	}
+	renderer, err := report.NewRenderer(format)
+	if err != nil {
+		return err
+	}
+
+	rep, err := report.Build(database, opts)
+	if err != nil {
+		return err
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	switch output {
+	case "":
+		path, err := report.Save(cfg.ReportsOutput, rep, renderer)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Report saved to %s\n", path)
+	case "-":
+		if err := renderer.Render(os.Stdout, rep); err != nil {
+			return fmt.Errorf("failed to render report: %w", err)
+		}
+	default:
+		if err := report.WriteFile(output, rep, renderer); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Report saved to %s\n", output)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/emilianohg/anchorman/internal/agent"
+	"github.com/emilianohg/anchorman/internal/config"
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/db"
+	"github.com/emilianohg/anchorman/internal/processor"
+)
+
+var reprocessCmd = &cobra.Command{
+	Use:   "reprocess",
+	Short: "Replace existing tasks by summarizing their commits again",
+	Long: `Summarize the commits of the tasks of a date range, project or company again,
+optionally with a different agent, and replace the tasks with the new ones.
+
+The tasks that will be replaced are listed before anything changes. Tasks
+outside of the range or filter that share a commit with one of them are
+replaced too.
+Old tasks are only deleted once their replacements are saved, so batches
+that fail keep their previous tasks. Tasks added by hand are never replaced.
+
+Examples:
+  anchorman reprocess --range last-week --dry-run  # Only show what would be replaced
+  anchorman reprocess --from 2025-01-01 --to 2025-01-31 --company Acme
+  anchorman reprocess --project api --range this-month --agent claude --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runReprocess(cmd); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func runReprocess(cmd *cobra.Command) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	database, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	cal := daterange.NewCalendar(cfg)
+	opts, err := processOptions(cmd, database, cal)
+	if err != nil {
+		return err
+	}
+	if opts.From.IsZero() && opts.To.IsZero() && opts.ProjectID == nil && opts.CompanyID == nil {
+		return fmt.Errorf("choose what to reprocess with --range, --from/--to, --project or --company")
+	}
+
+	// Create the agent and load the prompt templates first, so a bad --agent or
+	// template is reported before the confirmation
+	agentName, ag, err := agentFlag(cmd, cfg)
+	if err != nil {
+		return err
+	}
+	prompts, err := agent.LoadPromptTemplates(cfg)
+	if err != nil {
+		return err
+	}
+
+	replacement, err := processor.PlanReplacement(database, cal, opts)
+	if err != nil {
+		return err
+	}
+	if len(replacement.Tasks) == 0 {
+		fmt.Println("No tasks to reprocess")
+		return nil
+	}
+
+	fmt.Printf("Replacing %d tasks (%.1fh) derived from %d commits:\n",
+		len(replacement.Tasks), replacement.Hours(), len(replacement.Commits))
+	for _, t := range replacement.Tasks {
+		note := ""
+		if replacement.Related(t) {
+			note = " (shares commits with a selected task)"
+		}
+		fmt.Printf("  %s  %s  [%.1fh] %s%s\n",
+			t.TaskDate.Format(daterange.DateLayout), t.ProjectName, t.EstimatedHours, t.Description, note)
+	}
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		return nil
+	}
+	if yes, _ := cmd.Flags().GetBool("yes"); !yes && !confirm(fmt.Sprintf("Reprocess with %s?", agentName)) {
+		return fmt.Errorf("cancelled")
+	}
+
+	fmt.Printf("Processing with %s...\n", agentName)
+
+	// Ctrl+C stops after the current agent call; the tasks of the batches
+	// that weren't saved yet are kept
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency <= 0 {
+		concurrency = cfg.Concurrency
+	}
+
+	proc := processor.New(database, ag, cal).
+		WithConcurrency(concurrency).
+		WithChunking(processor.ChunkingFromConfig(cfg)).
+		WithPrompts(prompts).
+		WithReplacement(replacement)
+	proc.OnProgress(printProgress)
+
+	result, err := proc.ProcessCommits(ctx, replacement.Commits, processor.Options{})
+	return finishReprocessing(result, err)
+}
+
+// finishReprocessing reports the result of a reprocessing run. Unlike processing,
+// failed batches keep their previous tasks, so they are retried by reprocessing again.
+func finishReprocessing(result *processor.Result, err error) error {
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("interrupted after creating %d tasks; the tasks that weren't reprocessed were kept", result.TasksCreated)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %d tasks across %d projects\n", result.TasksCreated, len(result.Projects))
+
+	if failed := result.Failed(); len(failed) > 0 {
+		return fmt.Errorf("%d of %d projects failed; their previous tasks were kept, run 'anchorman reprocess' again to retry",
+			len(failed), len(result.Projects))
+	}
+	return nil
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
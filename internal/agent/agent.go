@@ -2,6 +2,7 @@ package agent
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"math"
 	"os/exec"
@@ -9,37 +10,61 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/emilianohg/anchorman/internal/models"
+	"github.com/emilianohg/anchorman/internal/config"
 )
 
 // TaskResult represents a processed task with its time estimate
 type TaskResult struct {
 	Description    string
 	EstimatedHours float64
+	CommitHashes   []string // (abbreviated) hashes of the commits the task was derived from, as returned by the agent
+	Category       string   // feature, fix, refactor, docs, test, chore or other; empty when unknown
 }
 
+// Agent turns the commits of a project into tasks. Implementations stop and
+// return ctx.Err() when ctx is cancelled.
 type Agent interface {
-	Process(projectName string, commits []models.RawCommit) ([]TaskResult, error)
+	Process(ctx context.Context, req Request) ([]TaskResult, error)
 }
 
-func New(agentType string) (Agent, error) {
-	switch agentType {
+// New creates the named agent using its [agents.<name>] settings from the config
+func New(name string, cfg *config.Config) (Agent, error) {
+	ac := cfg.Agent(name)
+
+	policy, err := newCallPolicy(name, ac)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ac.Type {
 	case "codex":
-		return &CodexAgent{}, nil
+		return &CodexAgent{policy: policy}, nil
 	case "claude":
-		return &ClaudeAgent{}, nil
+		return &ClaudeAgent{policy: policy}, nil
+	case "anthropic":
+		return NewAnthropicAgent(ac, policy)
+	case "openai":
+		return NewOpenAIAgent(ac, policy)
+	case "command":
+		return NewCommandAgent(name, ac, policy)
+	case "heuristic":
+		return &HeuristicAgent{}, nil
 	default:
-		return nil, fmt.Errorf("unknown agent type: %s", agentType)
+		return nil, fmt.Errorf("unknown agent type: %s", ac.Type)
 	}
 }
 
-type CodexAgent struct{}
+type CodexAgent struct {
+	policy callPolicy
+}
 
-func (a *CodexAgent) Process(projectName string, commits []models.RawCommit) ([]TaskResult, error) {
-	prompt := buildPrompt(projectName, commits)
+func (a *CodexAgent) Process(ctx context.Context, req Request) ([]TaskResult, error) {
+	return completeRequest(ctx, a.policy.wrap(a.run), req)
+}
 
+func (a *CodexAgent) run(ctx context.Context, prompt string) (string, error) {
 	// Use codex exec for non-interactive mode, pass prompt via stdin
-	cmd := exec.Command("codex", "exec", "-")
+	cmd := exec.CommandContext(ctx, "codex", "exec", "-")
 	cmd.Stdin = strings.NewReader(prompt)
 
 	var stdout, stderr bytes.Buffer
@@ -47,71 +72,44 @@ func (a *CodexAgent) Process(projectName string, commits []models.RawCommit) ([]
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("codex failed: %w\nstderr: %s", err, stderr.String())
+		return "", commandError("codex", err, stderr.String())
 	}
 
-	return parseResponse(stdout.String()), nil
+	return stdout.String(), nil
 }
 
-type ClaudeAgent struct{}
+type ClaudeAgent struct {
+	policy callPolicy
+}
 
-func (a *ClaudeAgent) Process(projectName string, commits []models.RawCommit) ([]TaskResult, error) {
-	prompt := buildPrompt(projectName, commits)
+func (a *ClaudeAgent) Process(ctx context.Context, req Request) ([]TaskResult, error) {
+	return completeRequest(ctx, a.policy.wrap(a.run), req)
+}
 
+func (a *ClaudeAgent) run(ctx context.Context, prompt string) (string, error) {
 	// Use claude -p for non-interactive print mode
-	cmd := exec.Command("claude", "-p", prompt)
+	cmd := exec.CommandContext(ctx, "claude", "-p", prompt)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("claude failed: %w\nstderr: %s", err, stderr.String())
+		return "", commandError("claude", err, stderr.String())
 	}
 
-	return parseResponse(stdout.String()), nil
-}
-
-func buildPrompt(projectName string, commits []models.RawCommit) string {
-	var sb strings.Builder
-
-	sb.WriteString("You are analyzing git commits to create human-readable task summaries for manager reports.\n\n")
-	sb.WriteString(fmt.Sprintf("Project: %s\n\n", projectName))
-	sb.WriteString("Commits:\n")
-
-	for _, c := range commits {
-		files := strings.Join(c.FilesChanged, ", ")
-		if len(files) > 100 {
-			files = files[:100] + "..."
-		}
-		sb.WriteString(fmt.Sprintf("- %s: %s (branch: %s, files: %s)\n",
-			c.Hash[:8], c.Message, c.Branch, files))
-	}
-
-	sb.WriteString("\nCreate a list of conceptual tasks that summarize the work done.\n")
-	sb.WriteString("- Group related commits into single tasks\n")
-	sb.WriteString("- Use plain, non-technical language suitable for managers\n")
-	sb.WriteString("- Focus on WHAT was accomplished, not HOW\n")
-	sb.WriteString("- Each task should be a single line starting with a verb (Implemented, Fixed, Added, Updated, etc.)\n")
-	sb.WriteString("\nFor each task, estimate the time spent based on:\n")
-	sb.WriteString("- Number of commits involved\n")
-	sb.WriteString("- Number and types of files changed\n")
-	sb.WriteString("- Complexity implied by commit messages\n")
-	sb.WriteString("\nUse 0.5 hour increments (minimum 0.5h). Examples: 0.5, 1.0, 1.5, 2.0, 2.5, etc.\n")
-	sb.WriteString("\nOutput format: - [X.Xh] Task description\n")
-	sb.WriteString("Examples:\n")
-	sb.WriteString("- [2.0h] Implemented user authentication system\n")
-	sb.WriteString("- [0.5h] Fixed login button styling\n")
-	sb.WriteString("- [1.5h] Refactored database connection handling\n")
-	sb.WriteString("\nOutput ONLY the tasks in this format:\n")
-
-	return sb.String()
+	return stdout.String(), nil
 }
 
 // timePattern matches [X.Xh] at the start of a task line
 var timePattern = regexp.MustCompile(`^\[(\d+\.?\d*)h\]\s*`)
 
-func parseResponse(response string) []TaskResult {
+// commitsPattern matches the "(commits: hash1, hash2)" list at the end of a task line
+var commitsPattern = regexp.MustCompile(`(?i)\s*\(commits?:\s*([0-9a-f,\s]+)\)\s*$`)
+
+// parseLines reads "- [X.Xh] Task description (commits: hash1, hash2)" lines.
+// It is the fallback for agents that ignore the JSON output format.
+func parseLines(response string) []TaskResult {
 	var tasks []TaskResult
 	lines := strings.Split(response, "\n")
 
@@ -121,11 +119,14 @@ func parseResponse(response string) []TaskResult {
 			continue
 		}
 
-		// Remove leading "- " or "* " if present
+		// Only bullet or [X.Xh] lines are tasks, so preambles like
+		// "Here are the tasks:" are skipped. Remove the leading "- " or "* ".
 		if strings.HasPrefix(line, "- ") {
 			line = strings.TrimPrefix(line, "- ")
 		} else if strings.HasPrefix(line, "* ") {
 			line = strings.TrimPrefix(line, "* ")
+		} else if !timePattern.MatchString(line) {
+			continue
 		}
 
 		// Skip empty or very short lines
@@ -138,6 +139,15 @@ func parseResponse(response string) []TaskResult {
 			EstimatedHours: 0.5, // default
 		}
 
+		if match := commitsPattern.FindStringSubmatch(line); match != nil {
+			for _, hash := range strings.Split(match[1], ",") {
+				if hash = strings.TrimSpace(hash); hash != "" {
+					result.CommitHashes = append(result.CommitHashes, hash)
+				}
+			}
+			line = commitsPattern.ReplaceAllString(line, "")
+		}
+
 		if match := timePattern.FindStringSubmatch(line); match != nil {
 			if hours, err := strconv.ParseFloat(match[1], 64); err == nil {
 				result.EstimatedHours = roundToHalfHour(hours)
@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/emilianohg/anchorman/internal/config"
+)
+
+// Prompt delivery modes for command agents
+const (
+	PromptStdin = "stdin" // prompt is written to the command's stdin
+	PromptArg   = "arg"   // prompt is passed as an argument
+	PromptFile  = "file"  // prompt is written to a temporary file whose path is passed as an argument
+)
+
+// CommandAgent runs any executable configured in an [agents.<name>] table, so
+// new AI CLIs can be used without code changes
+type CommandAgent struct {
+	name    string
+	command string
+	args    []string
+	input   string
+	policy  callPolicy
+}
+
+func NewCommandAgent(name string, ac config.AgentConfig, policy callPolicy) (*CommandAgent, error) {
+	if ac.Command == "" {
+		return nil, fmt.Errorf("agent %s: command is required", name)
+	}
+
+	input := ac.PromptInput
+	if input == "" {
+		input = PromptStdin
+	}
+	if input != PromptStdin && input != PromptArg && input != PromptFile {
+		return nil, fmt.Errorf("agent %s: unknown prompt_input %q (expected stdin, arg or file)", name, input)
+	}
+
+	return &CommandAgent{
+		name:    name,
+		command: ac.Command,
+		args:    ac.Args,
+		input:   input,
+		policy:  policy,
+	}, nil
+}
+
+func (a *CommandAgent) Process(ctx context.Context, req Request) ([]TaskResult, error) {
+	return completeRequest(ctx, a.policy.wrap(a.run), req)
+}
+
+func (a *CommandAgent) run(ctx context.Context, prompt string) (string, error) {
+	var promptFile string
+	if a.input == PromptFile {
+		f, err := os.CreateTemp("", "anchorman-prompt-*.txt")
+		if err != nil {
+			return "", fmt.Errorf("failed to write prompt file: %w", err)
+		}
+		defer os.Remove(f.Name())
+
+		_, err = f.WriteString(prompt)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to write prompt file: %w", err)
+		}
+		promptFile = f.Name()
+	}
+
+	cmd := exec.CommandContext(ctx, a.command, a.buildArgs(prompt, promptFile)...)
+	// Don't wait for children of a killed command that still hold its output open
+	cmd.WaitDelay = time.Second
+	if a.input == PromptStdin {
+		cmd.Stdin = strings.NewReader(prompt)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", commandError(a.name, err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
+
+// buildArgs replaces the {prompt} and {prompt_file} placeholders in the configured
+// arguments. Without a placeholder, the prompt or file path is appended as the last argument.
+func (a *CommandAgent) buildArgs(prompt, promptFile string) []string {
+	placeholder, value := "", ""
+	switch a.input {
+	case PromptArg:
+		placeholder, value = "{prompt}", prompt
+	case PromptFile:
+		placeholder, value = "{prompt_file}", promptFile
+	}
+
+	args := make([]string, 0, len(a.args)+1)
+	replaced := false
+	for _, arg := range a.args {
+		if placeholder != "" && strings.Contains(arg, placeholder) {
+			arg = strings.ReplaceAll(arg, placeholder, value)
+			replaced = true
+		}
+		args = append(args, arg)
+	}
+
+	if placeholder != "" && !replaced {
+		args = append(args, value)
+	}
+	return args
+}
@@ -0,0 +1,244 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/emilianohg/anchorman/internal/models"
+)
+
+// HeuristicAgent summarizes commits without an AI model. It groups commits by
+// conventional-commit type and scope, branch name and shared files, and derives
+// task descriptions from the commit subjects. Its output is deterministic.
+type HeuristicAgent struct{}
+
+// conventionalPattern matches "type(scope)!: subject" commit subjects
+var conventionalPattern = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?!?:\s*(.+)$`)
+
+// defaultBranches are too broad to group commits by
+var defaultBranches = map[string]bool{"main": true, "master": true, "develop": true, "dev": true, "trunk": true}
+
+// categories maps conventional-commit types to task categories
+var categories = map[string]string{
+	"feat":     "feature",
+	"feature":  "feature",
+	"fix":      "fix",
+	"bugfix":   "fix",
+	"hotfix":   "fix",
+	"refactor": "refactor",
+	"perf":     "refactor",
+	"style":    "refactor",
+	"docs":     "docs",
+	"doc":      "docs",
+	"test":     "test",
+	"tests":    "test",
+	"chore":    "chore",
+	"build":    "chore",
+	"ci":       "chore",
+}
+
+const (
+	maxSubjectsPerTask = 3
+	maxHeuristicHours  = 8.0
+	linesPerHalfHour   = 100.0 // changed lines that add half an hour; each doubling adds another
+)
+
+type heuristicCommit struct {
+	commit   models.RawCommit
+	kind     string // conventional-commit type, lowercased; empty when not conventional
+	scope    string
+	subject  string // cleaned-up subject line
+	category string
+}
+
+type heuristicGroup struct {
+	label   string // scope or branch shown before the subjects
+	commits []heuristicCommit
+}
+
+func (a *HeuristicAgent) Process(ctx context.Context, req Request) ([]TaskResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var tasks []TaskResult
+	for _, g := range groupCommits(req.Commits) {
+		tasks = append(tasks, g.task())
+	}
+	return tasks, nil
+}
+
+func parseCommit(c models.RawCommit) heuristicCommit {
+	subject := strings.TrimSpace(strings.SplitN(c.Message, "\n", 2)[0])
+	hc := heuristicCommit{commit: c, category: "other"}
+
+	if m := conventionalPattern.FindStringSubmatch(subject); m != nil {
+		kind := strings.ToLower(m[1])
+		if category, ok := categories[kind]; ok {
+			hc.kind = kind
+			hc.scope = strings.TrimSpace(m[2])
+			hc.category = category
+			subject = m[3]
+		}
+	}
+
+	hc.subject = cleanSubject(subject)
+	return hc
+}
+
+// cleanSubject capitalizes a subject and removes trailing punctuation
+func cleanSubject(s string) string {
+	s = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), ".;:"))
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// groupCommits groups conventional commits by type and scope, other commits on a
+// feature branch by branch, and the rest by shared files. Groups are ordered by
+// their first commit.
+func groupCommits(commits []models.RawCommit) []*heuristicGroup {
+	var groups []*heuristicGroup
+	byKey := make(map[string]*heuristicGroup)
+	var rest []heuristicCommit
+
+	for _, c := range commits {
+		hc := parseCommit(c)
+
+		var key, label string
+		switch {
+		case hc.kind != "":
+			key, label = "type:"+hc.category+":"+strings.ToLower(hc.scope), hc.scope
+		case c.Branch != "" && !defaultBranches[c.Branch]:
+			key, label = "branch:"+c.Branch, path.Base(c.Branch)
+		default:
+			rest = append(rest, hc)
+			continue
+		}
+
+		g, ok := byKey[key]
+		if !ok {
+			g = &heuristicGroup{label: label}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.commits = append(g.commits, hc)
+	}
+
+	groups = append(groups, groupByFiles(rest)...)
+	return groups
+}
+
+// groupByFiles puts commits that touch a common file into the same group
+func groupByFiles(commits []heuristicCommit) []*heuristicGroup {
+	parent := make([]int, len(commits))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	owner := make(map[string]int)
+	for i, c := range commits {
+		for _, f := range c.commit.FilesChanged {
+			if j, ok := owner[f]; ok {
+				// Keep the earliest commit as root so groups stay in commit order
+				a, b := find(i), find(j)
+				if a < b {
+					parent[b] = a
+				} else {
+					parent[a] = b
+				}
+			} else {
+				owner[f] = i
+			}
+		}
+	}
+
+	var groups []*heuristicGroup
+	byRoot := make(map[int]*heuristicGroup)
+	for i, c := range commits {
+		root := find(i)
+		g, ok := byRoot[root]
+		if !ok {
+			g = &heuristicGroup{}
+			byRoot[root] = g
+			groups = append(groups, g)
+		}
+		g.commits = append(g.commits, c)
+	}
+	return groups
+}
+
+func (g *heuristicGroup) task() TaskResult {
+	var subjects, hashes []string
+	seen := make(map[string]bool)
+	files := make(map[string]bool)
+	counts := make(map[string]int)
+	lines := 0
+
+	for _, c := range g.commits {
+		hashes = append(hashes, c.commit.Hash)
+		lines += c.commit.Insertions + c.commit.Deletions
+		counts[c.category]++
+		for _, f := range c.commit.FilesChanged {
+			files[f] = true
+		}
+		key := strings.ToLower(c.subject)
+		if c.subject != "" && !seen[key] {
+			seen[key] = true
+			subjects = append(subjects, c.subject)
+		}
+	}
+
+	description := strings.Join(subjects, "; ")
+	if len(subjects) > maxSubjectsPerTask {
+		description = fmt.Sprintf("%s (+%d more)",
+			strings.Join(subjects[:maxSubjectsPerTask], "; "), len(subjects)-maxSubjectsPerTask)
+	}
+	if description == "" {
+		description = fmt.Sprintf("Made %d changes", len(g.commits))
+	}
+	if g.label != "" {
+		description = cleanSubject(g.label) + ": " + description
+	}
+
+	return TaskResult{
+		Description:    description,
+		EstimatedHours: estimateHours(len(g.commits), len(files), lines),
+		CommitHashes:   hashes,
+		Category:       topCategory(counts),
+	}
+}
+
+// estimateHours guesses the time spent from the number of commits, files changed and
+// lines added plus removed. Lines count logarithmically, so generated files and
+// lockfiles don't dominate the estimate.
+func estimateHours(commits, files, lines int) float64 {
+	hours := 0.25*float64(commits) + 0.1*float64(files) + 0.5*math.Log2(1+float64(lines)/linesPerHalfHour)
+	return math.Min(roundToHalfHour(hours), maxHeuristicHours)
+}
+
+// topCategory returns the most common category, preferring the order of Categories on ties
+func topCategory(counts map[string]int) string {
+	best := "other"
+	for _, c := range Categories {
+		if counts[c] > counts[best] {
+			best = c
+		}
+	}
+	return best
+}
@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/emilianohg/anchorman/internal/models"
+)
+
+func TestEstimateHours(t *testing.T) {
+	tests := []struct {
+		name                  string
+		commits, files, lines int
+		want                  float64
+	}{
+		{name: "nothing changed", want: 0.5},
+		{name: "small commit", commits: 1, files: 1, lines: 0, want: 0.5},
+		{name: "a few commits", commits: 2, files: 3, lines: 0, want: 1.0},
+		{name: "hundred lines", commits: 1, files: 1, lines: 100, want: 1.0},
+		{name: "three hundred lines", commits: 1, files: 1, lines: 300, want: 1.5},
+		{name: "larger change", commits: 4, files: 10, lines: 700, want: 3.5},
+		{name: "huge lockfile stays below the cap", commits: 1, files: 1, lines: 100000, want: 5.5},
+		{name: "capped", commits: 40, files: 100, lines: 1000, want: maxHeuristicHours},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := estimateHours(tt.commits, tt.files, tt.lines); got != tt.want {
+				t.Errorf("estimateHours(%d, %d, %d) = %v, want %v", tt.commits, tt.files, tt.lines, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeuristicAgent(t *testing.T) {
+	commit := func(hash, message, branch string, files ...string) models.RawCommit {
+		return models.RawCommit{Hash: hash, Message: message, Branch: branch, FilesChanged: files}
+	}
+
+	type task struct {
+		Description string
+		Hashes      []string
+		Category    string
+	}
+
+	tests := []struct {
+		name    string
+		commits []models.RawCommit
+		want    []task
+	}{
+		{
+			name: "conventional commits by type and scope",
+			commits: []models.RawCommit{
+				commit("a1", "feat(auth): add login form.", "main", "auth/login.go"),
+				commit("a2", "fix(api): handle empty input\n\nLonger body", "main", "api/handler.go"),
+				commit("a3", "feat(Auth): add logout", "main", "auth/logout.go"),
+				commit("a4", "feat!: drop v1 endpoints", "main", "api/v1.go"),
+			},
+			want: []task{
+				{"Auth: Add login form; Add logout", []string{"a1", "a3"}, "feature"},
+				{"Api: Handle empty input", []string{"a2"}, "fix"},
+				{"Drop v1 endpoints", []string{"a4"}, "feature"},
+			},
+		},
+		{
+			name: "feature branches",
+			commits: []models.RawCommit{
+				commit("b1", "Start the export", "feature/csv-export", "export.go"),
+				commit("b2", "wip", "feature/csv-export", "export_test.go"),
+				commit("b3", "Unrelated tweak", "main", "main.go"),
+			},
+			want: []task{
+				{"Csv-export: Start the export; Wip", []string{"b1", "b2"}, "other"},
+				{"Unrelated tweak", []string{"b3"}, "other"},
+			},
+		},
+		{
+			name: "shared files",
+			commits: []models.RawCommit{
+				commit("c1", "Update the README", "main", "README.md"),
+				commit("c2", "Tune the parser", "main", "parser.go"),
+				commit("c3", "Document the parser", "main", "README.md", "parser.go"),
+				commit("c4", "Bump version", "", "VERSION"),
+			},
+			want: []task{
+				{"Update the README; Tune the parser; Document the parser", []string{"c1", "c2", "c3"}, "other"},
+				{"Bump version", []string{"c4"}, "other"},
+			},
+		},
+		{
+			name: "repeated and many subjects",
+			commits: []models.RawCommit{
+				commit("d1", "docs: fix typo", "main", "a.md"),
+				commit("d2", "docs: Fix typo", "main", "b.md"),
+				commit("d3", "docs: add intro", "main", "c.md"),
+				commit("d4", "docs: add FAQ", "main", "d.md"),
+				commit("d5", "docs: add glossary", "main", "e.md"),
+			},
+			want: []task{
+				{"Fix typo; Add intro; Add FAQ (+1 more)", []string{"d1", "d2", "d3", "d4", "d5"}, "docs"},
+			},
+		},
+		{
+			name: "unknown types are not conventional",
+			commits: []models.RawCommit{
+				commit("e1", "note: remember the milk", "main", "notes.txt"),
+			},
+			want: []task{
+				{"Note: remember the milk", []string{"e1"}, "other"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := (&HeuristicAgent{}).Process(context.Background(), Request{Project: "project", Commits: tt.commits})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []task
+			for _, tk := range tasks {
+				got = append(got, task{tk.Description, tk.CommitHashes, tk.Category})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Process() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeuristicAgentCountsLines(t *testing.T) {
+	commit := func(hash string, insertions, deletions int) models.RawCommit {
+		return models.RawCommit{
+			Hash:         hash,
+			Message:      "fix(api): handle empty input",
+			FilesChanged: []string{"api/handler.go"},
+			Insertions:   insertions,
+			Deletions:    deletions,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		commits []models.RawCommit
+		want    float64
+	}{
+		{name: "small change", commits: []models.RawCommit{commit("a1", 5, 2)}, want: 0.5},
+		{name: "large change", commits: []models.RawCommit{commit("a1", 250, 50)}, want: 1.5},
+		{name: "lines of all commits add up", commits: []models.RawCommit{commit("a1", 100, 0), commit("a2", 150, 50)}, want: 1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := (&HeuristicAgent{}).Process(context.Background(), Request{Project: "api", Commits: tt.commits})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tasks) != 1 {
+				t.Fatalf("got %d tasks, want 1", len(tasks))
+			}
+			if tasks[0].EstimatedHours != tt.want {
+				t.Errorf("EstimatedHours = %v, want %v", tasks[0].EstimatedHours, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/emilianohg/anchorman/internal/config"
+)
+
+const (
+	defaultAnthropicURL = "https://api.anthropic.com"
+	defaultOpenAIURL    = "https://api.openai.com/v1"
+	anthropicVersion    = "2023-06-01"
+	defaultMaxTokens    = 4096
+)
+
+// AnthropicAgent calls the Anthropic Messages API directly
+type AnthropicAgent struct {
+	baseURL   string
+	model     string
+	apiKey    string
+	maxTokens int
+	policy    callPolicy
+	client    *http.Client
+}
+
+func NewAnthropicAgent(ac config.AgentConfig, policy callPolicy) (*AnthropicAgent, error) {
+	if ac.Model == "" {
+		return nil, fmt.Errorf("anthropic agent: model is required")
+	}
+
+	apiKey, err := apiKey(ac, "ANTHROPIC_API_KEY", true)
+	if err != nil {
+		return nil, err
+	}
+
+	maxTokens := ac.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultMaxTokens
+	}
+
+	return &AnthropicAgent{
+		baseURL:   baseURL(ac, defaultAnthropicURL),
+		model:     ac.Model,
+		apiKey:    apiKey,
+		maxTokens: maxTokens,
+		policy:    policy,
+		client:    &http.Client{},
+	}, nil
+}
+
+func (a *AnthropicAgent) Process(ctx context.Context, req Request) ([]TaskResult, error) {
+	return completeRequest(ctx, a.policy.wrap(a.run), req)
+}
+
+func (a *AnthropicAgent) run(ctx context.Context, prompt string) (string, error) {
+	body := map[string]any{
+		"model":      a.model,
+		"max_tokens": a.maxTokens,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	headers := map[string]string{
+		"x-api-key":         a.apiKey,
+		"anthropic-version": anthropicVersion,
+	}
+
+	var resp struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := postJSON(ctx, a.client, a.baseURL+"/v1/messages", headers, body, &resp); err != nil {
+		return "", fmt.Errorf("anthropic request failed: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, c := range resp.Content {
+		if c.Type == "text" {
+			sb.WriteString(c.Text)
+		}
+	}
+	return sb.String(), nil
+}
+
+// OpenAIAgent calls an OpenAI-compatible /chat/completions endpoint, such as
+// the OpenAI API or a local model server
+type OpenAIAgent struct {
+	baseURL string
+	model   string
+	apiKey  string // optional for local servers
+	policy  callPolicy
+	client  *http.Client
+}
+
+func NewOpenAIAgent(ac config.AgentConfig, policy callPolicy) (*OpenAIAgent, error) {
+	if ac.Model == "" {
+		return nil, fmt.Errorf("openai agent: model is required")
+	}
+
+	// Only the public API needs a key; local servers usually accept none
+	base := baseURL(ac, defaultOpenAIURL)
+	apiKey, err := apiKey(ac, "OPENAI_API_KEY", base == defaultOpenAIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenAIAgent{
+		baseURL: base,
+		model:   ac.Model,
+		apiKey:  apiKey,
+		policy:  policy,
+		client:  &http.Client{},
+	}, nil
+}
+
+func (a *OpenAIAgent) Process(ctx context.Context, req Request) ([]TaskResult, error) {
+	return completeRequest(ctx, a.policy.wrap(a.run), req)
+}
+
+func (a *OpenAIAgent) run(ctx context.Context, prompt string) (string, error) {
+	body := map[string]any{
+		"model": a.model,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+	headers := map[string]string{}
+	if a.apiKey != "" {
+		headers["Authorization"] = "Bearer " + a.apiKey
+	}
+
+	var resp struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := postJSON(ctx, a.client, a.baseURL+"/chat/completions", headers, body, &resp); err != nil {
+		return "", fmt.Errorf("openai request failed: %w", err)
+	}
+
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("openai request failed: response has no choices")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+func baseURL(ac config.AgentConfig, fallback string) string {
+	if ac.BaseURL == "" {
+		return fallback
+	}
+	return strings.TrimRight(ac.BaseURL, "/")
+}
+
+// apiKey reads the API key from the configured environment variable, or from
+// fallbackEnv when none is configured
+func apiKey(ac config.AgentConfig, fallbackEnv string, required bool) (string, error) {
+	env := ac.APIKeyEnv
+	if env == "" {
+		env = fallbackEnv
+	}
+
+	key := os.Getenv(env)
+	if key == "" && required {
+		return "", fmt.Errorf("%s agent: environment variable %s is not set", ac.Type, env)
+	}
+	return key, nil
+}
+
+// postJSON sends body as JSON and decodes the JSON response into out.
+// Network errors, rate limits and server errors are returned as transient.
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return transient(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return transient(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return transient(err)
+		}
+		return err
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
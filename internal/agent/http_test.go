@@ -0,0 +1,256 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/emilianohg/anchorman/internal/config"
+)
+
+// chatRequest is the body sent by both HTTP agents
+type chatRequest struct {
+	Model     string `json:"model"`
+	MaxTokens int    `json:"max_tokens"`
+	Messages  []struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	} `json:"messages"`
+}
+
+func decodeChatRequest(t *testing.T, r *http.Request) chatRequest {
+	t.Helper()
+	var body chatRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		t.Errorf("invalid request body: %v", err)
+	}
+	return body
+}
+
+func TestAnthropicAgentRequest(t *testing.T) {
+	t.Setenv("TEST_ANTHROPIC_KEY", "secret")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/messages" {
+			t.Errorf("request = %s %s, want POST /v1/messages", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("x-api-key"); got != "secret" {
+			t.Errorf("x-api-key = %q, want %q", got, "secret")
+		}
+		if got := r.Header.Get("anthropic-version"); got != anthropicVersion {
+			t.Errorf("anthropic-version = %q, want %q", got, anthropicVersion)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", got)
+		}
+
+		body := decodeChatRequest(t, r)
+		if body.Model != "test-model" || body.MaxTokens != 1000 {
+			t.Errorf("model, max_tokens = %q, %d, want test-model, 1000", body.Model, body.MaxTokens)
+		}
+		if len(body.Messages) != 1 || body.Messages[0].Role != "user" || body.Messages[0].Content != "hello" {
+			t.Errorf("messages = %+v, want one user message with the prompt", body.Messages)
+		}
+
+		fmt.Fprint(w, `{"content": [{"type": "text", "text": "part one, "}, {"type": "tool_use"}, {"type": "text", "text": "part two"}]}`)
+	}))
+	defer srv.Close()
+
+	a, err := NewAnthropicAgent(config.AgentConfig{
+		Type:      "anthropic",
+		BaseURL:   srv.URL + "/",
+		Model:     "test-model",
+		APIKeyEnv: "TEST_ANTHROPIC_KEY",
+		MaxTokens: 1000,
+	}, callPolicy{name: "anthropic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := a.run(context.Background(), "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "part one, part two"; got != want {
+		t.Errorf("run() = %q, want %q", got, want)
+	}
+}
+
+func TestAnthropicAgentRequiresKey(t *testing.T) {
+	t.Setenv("TEST_ANTHROPIC_KEY", "")
+
+	_, err := NewAnthropicAgent(config.AgentConfig{Type: "anthropic", Model: "m", APIKeyEnv: "TEST_ANTHROPIC_KEY"}, callPolicy{})
+	if err == nil {
+		t.Fatal("expected an error when the API key is not set")
+	}
+}
+
+func TestOpenAIAgentRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		auth string
+	}{
+		{name: "with key", key: "secret", auth: "Bearer secret"},
+		{name: "local server without key", key: "", auth: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_OPENAI_KEY", tt.key)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/v1/chat/completions" {
+					t.Errorf("request = %s %s, want POST /v1/chat/completions", r.Method, r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != tt.auth {
+					t.Errorf("Authorization = %q, want %q", got, tt.auth)
+				}
+
+				body := decodeChatRequest(t, r)
+				if body.Model != "local-model" {
+					t.Errorf("model = %q, want local-model", body.Model)
+				}
+				if len(body.Messages) != 1 || body.Messages[0].Role != "user" || body.Messages[0].Content != "hello" {
+					t.Errorf("messages = %+v, want one user message with the prompt", body.Messages)
+				}
+
+				fmt.Fprint(w, `{"choices": [{"message": {"role": "assistant", "content": "answer"}}]}`)
+			}))
+			defer srv.Close()
+
+			a, err := NewOpenAIAgent(config.AgentConfig{
+				Type:      "openai",
+				BaseURL:   srv.URL + "/v1",
+				Model:     "local-model",
+				APIKeyEnv: "TEST_OPENAI_KEY",
+			}, callPolicy{name: "openai"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := a.run(context.Background(), "hello")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != "answer" {
+				t.Errorf("run() = %q, want %q", got, "answer")
+			}
+		})
+	}
+}
+
+func TestOpenAIAgentNoChoices(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"choices": []}`)
+	}))
+	defer srv.Close()
+
+	a, err := NewOpenAIAgent(config.AgentConfig{Type: "openai", BaseURL: srv.URL, Model: "m"}, callPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.run(context.Background(), "hello"); err == nil {
+		t.Fatal("expected an error for a response without choices")
+	}
+}
+
+func TestPostJSONStatus(t *testing.T) {
+	tests := []struct {
+		status    int
+		transient bool
+	}{
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusBadGateway, true},
+		{http.StatusServiceUnavailable, true},
+		{529, true}, // overloaded
+		{http.StatusBadRequest, false},
+		{http.StatusUnauthorized, false},
+		{http.StatusForbidden, false},
+		{http.StatusNotFound, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "something went wrong", tt.status)
+			}))
+			defer srv.Close()
+
+			var out struct{}
+			err := postJSON(context.Background(), srv.Client(), srv.URL, nil, map[string]string{}, &out)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			var te *transientError
+			if got := errors.As(err, &te); got != tt.transient {
+				t.Errorf("transient = %v, want %v (err: %v)", got, tt.transient, err)
+			}
+		})
+	}
+}
+
+func TestPostJSONInvalidResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "not json")
+	}))
+	defer srv.Close()
+
+	var out struct{}
+	err := postJSON(context.Background(), srv.Client(), srv.URL, nil, map[string]string{}, &out)
+	var te *transientError
+	if err == nil || errors.As(err, &te) {
+		t.Fatalf("postJSON() error = %v, want a non-transient error", err)
+	}
+}
+
+func TestHTTPAgentRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int // returned by the first request
+		requests int32
+		wantErr  bool
+	}{
+		{name: "rate limit is retried", status: http.StatusTooManyRequests, requests: 2},
+		{name: "server error is retried", status: http.StatusServiceUnavailable, requests: 2},
+		{name: "client error is not retried", status: http.StatusBadRequest, requests: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) == 1 {
+					http.Error(w, "try later", tt.status)
+					return
+				}
+				fmt.Fprint(w, `{"choices": [{"message": {"content": "answer"}}]}`)
+			}))
+			defer srv.Close()
+
+			a, err := NewOpenAIAgent(config.AgentConfig{Type: "openai", BaseURL: srv.URL, Model: "m"},
+				callPolicy{name: "openai", retries: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := a.policy.wrap(a.run)(context.Background(), "hello")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != "answer" {
+				t.Errorf("output = %q, want %q", got, "answer")
+			}
+			if n := requests.Load(); n != tt.requests {
+				t.Errorf("requests = %d, want %d", n, tt.requests)
+			}
+		})
+	}
+}
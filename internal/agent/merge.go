@@ -0,0 +1,100 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Merger is implemented by agents that can combine the tasks summarized from
+// several chunks of a project's commits into a deduplicated list
+type Merger interface {
+	Merge(ctx context.Context, req Request, tasks []TaskResult) ([]TaskResult, error)
+}
+
+func buildMergePrompt(req Request, tasks []TaskResult) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("You are cleaning up task summaries for a manager report.\n")
+	sb.WriteString("The commits of one project were summarized in several parts, so some tasks may overlap.\n\n")
+	sb.WriteString(fmt.Sprintf("Project: %s\n\n", req.Project))
+
+	// Custom instructions decide the wording (e.g. the language) of the merged tasks too
+	if req.Prompt != nil {
+		instructions, err := req.instructions()
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString("The tasks were written following these instructions:\n")
+		sb.WriteString(instructions)
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString("Tasks:\n")
+
+	for _, t := range tasks {
+		sb.WriteString(fmt.Sprintf("- [%.1fh] %s (commits: %s)\n",
+			t.EstimatedHours, t.Description, strings.Join(t.CommitHashes, ", ")))
+	}
+
+	sb.WriteString("\nMerge duplicate and closely related tasks into one.\n")
+	sb.WriteString("- Keep tasks that are unrelated as they are\n")
+	sb.WriteString("- A merged task covers the commits of all tasks it replaces\n")
+	sb.WriteString("- A merged task's hours are the sum of the hours of the tasks it replaces\n")
+	sb.WriteString("- Keep the wording style: one line starting with a verb\n")
+	sb.WriteString(languageInstruction(req.Language))
+	sb.WriteString(outputContract)
+
+	return sb.String(), nil
+}
+
+func completeMerge(ctx context.Context, run runFunc, req Request, tasks []TaskResult) ([]TaskResult, error) {
+	prompt, err := buildMergePrompt(req, tasks)
+	if err != nil {
+		return nil, err
+	}
+	return complete(ctx, run, prompt)
+}
+
+func (a *CodexAgent) Merge(ctx context.Context, req Request, tasks []TaskResult) ([]TaskResult, error) {
+	return completeMerge(ctx, a.policy.wrap(a.run), req, tasks)
+}
+
+func (a *ClaudeAgent) Merge(ctx context.Context, req Request, tasks []TaskResult) ([]TaskResult, error) {
+	return completeMerge(ctx, a.policy.wrap(a.run), req, tasks)
+}
+
+func (a *AnthropicAgent) Merge(ctx context.Context, req Request, tasks []TaskResult) ([]TaskResult, error) {
+	return completeMerge(ctx, a.policy.wrap(a.run), req, tasks)
+}
+
+func (a *OpenAIAgent) Merge(ctx context.Context, req Request, tasks []TaskResult) ([]TaskResult, error) {
+	return completeMerge(ctx, a.policy.wrap(a.run), req, tasks)
+}
+
+func (a *CommandAgent) Merge(ctx context.Context, req Request, tasks []TaskResult) ([]TaskResult, error) {
+	return completeMerge(ctx, a.policy.wrap(a.run), req, tasks)
+}
+
+// Merge combines tasks with the same description, adding up their hours and commits
+func (a *HeuristicAgent) Merge(ctx context.Context, req Request, tasks []TaskResult) ([]TaskResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var merged []TaskResult
+	index := make(map[string]int)
+	for _, t := range tasks {
+		key := strings.ToLower(t.Description)
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			t.CommitHashes = append([]string(nil), t.CommitHashes...)
+			merged = append(merged, t)
+			continue
+		}
+		merged[i].EstimatedHours = min(merged[i].EstimatedHours+t.EstimatedHours, maxHeuristicHours)
+		merged[i].CommitHashes = append(merged[i].CommitHashes, t.CommitHashes...)
+	}
+	return merged, nil
+}
@@ -0,0 +1,254 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/emilianohg/anchorman/internal/config"
+	"github.com/emilianohg/anchorman/internal/locale"
+	"github.com/emilianohg/anchorman/internal/models"
+)
+
+// Request is a batch of commits of a single project to summarize
+type Request struct {
+	Project string
+	Company string // empty when the project has no company
+	Commits []models.RawCommit
+	Prompt  *PromptTemplate // custom instructions; nil = built-in prompt
+
+	Language string // language of the task descriptions, e.g. "Spanish"; empty = not specified
+}
+
+// PromptTemplate holds custom instructions for the agent, written as a text/template.
+// The commit list and the output format are appended to the rendered instructions,
+// so templates only describe how tasks should be written and estimated.
+type PromptTemplate struct {
+	Name string
+	tmpl *template.Template
+}
+
+// PromptData is passed to prompt templates
+type PromptData struct {
+	Project  string
+	Company  string // empty when the project has no company
+	Language string // from the company's locale; empty when not configured
+	Commits  []models.RawCommit
+}
+
+var promptFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"short": shortHash,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// defaultInstructions are used when no prompt template is configured
+const defaultInstructions = `You are analyzing git commits to create human-readable task summaries for manager reports.
+
+Create a list of conceptual tasks that summarize the work done.
+- Group related commits into single tasks
+- Use plain, non-technical language suitable for managers
+- Focus on WHAT was accomplished, not HOW
+- Each task should be a single line starting with a verb (Implemented, Fixed, Added, Updated, etc.)
+
+For each task, estimate the time spent based on:
+- Number of commits involved
+- Number and types of files changed
+- Number of lines added and removed (generated files and lockfiles can inflate them)
+- Complexity implied by commit messages
+
+Use 0.5 hour increments (minimum 0.5h). Examples: 0.5, 1.0, 1.5, 2.0, 2.5, etc.`
+
+var defaultPrompt = mustParsePrompt("default", defaultInstructions)
+
+// ParsePromptTemplate parses the text of a prompt template
+func ParsePromptTemplate(name, text string) (*PromptTemplate, error) {
+	tmpl, err := template.New(name).Funcs(promptFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prompt template %s: %w", name, err)
+	}
+	return &PromptTemplate{Name: name, tmpl: tmpl}, nil
+}
+
+func mustParsePrompt(name, text string) *PromptTemplate {
+	t, err := ParsePromptTemplate(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// LoadPromptTemplate loads a prompt template by name from ~/.anchorman/prompts
+// (e.g. "spanish" matches spanish.tmpl), or by file path
+func LoadPromptTemplate(name string) (*PromptTemplate, error) {
+	path, err := findPromptTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt template: %w", err)
+	}
+
+	return ParsePromptTemplate(name, string(content))
+}
+
+func findPromptTemplate(name string) (string, error) {
+	// Explicit file path
+	if strings.ContainsRune(name, os.PathSeparator) {
+		if _, err := os.Stat(name); err != nil {
+			return "", fmt.Errorf("prompt template not found: %s", name)
+		}
+		return name, nil
+	}
+
+	dir, err := config.PromptsDir()
+	if err != nil {
+		return "", err
+	}
+
+	for _, c := range []string{filepath.Join(dir, name), filepath.Join(dir, name+".tmpl")} {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			return c, nil
+		}
+	}
+
+	return "", fmt.Errorf("prompt template not found: %s (looked in %s)", name, dir)
+}
+
+// PromptTemplates picks the prompt template and language configured for each project
+type PromptTemplates struct {
+	cfg       *config.Config
+	templates map[string]*PromptTemplate // by name in the config
+}
+
+// LoadPromptTemplates loads every prompt template referenced by the config and checks
+// the company locales, so mistakes are reported before any agent is called
+func LoadPromptTemplates(cfg *config.Config) (*PromptTemplates, error) {
+	names := []string{cfg.PromptTemplate}
+	for name, cc := range cfg.Companies {
+		names = append(names, cc.PromptTemplate)
+		if _, err := locale.Parse(cc.Locale); err != nil {
+			return nil, fmt.Errorf("company %s: %w", name, err)
+		}
+	}
+	for _, pc := range cfg.Projects {
+		names = append(names, pc.PromptTemplate)
+	}
+
+	pt := &PromptTemplates{cfg: cfg, templates: make(map[string]*PromptTemplate)}
+	for _, name := range names {
+		if name == "" || pt.templates[name] != nil {
+			continue
+		}
+		t, err := LoadPromptTemplate(name)
+		if err != nil {
+			return nil, err
+		}
+		pt.templates[name] = t
+	}
+	return pt, nil
+}
+
+// For returns the template of a project, or nil for the built-in prompt
+func (pt *PromptTemplates) For(project, company string) *PromptTemplate {
+	if pt == nil {
+		return nil
+	}
+	return pt.templates[pt.cfg.PromptTemplateFor(project, company)]
+}
+
+// Language returns the language of the company's locale, or "" when none is configured
+func (pt *PromptTemplates) Language(company string) string {
+	if pt == nil || company == "" {
+		return ""
+	}
+	code := pt.cfg.Company(company).Locale
+	if code == "" {
+		return ""
+	}
+	l, err := locale.Parse(code)
+	if err != nil {
+		return ""
+	}
+	return l.Language
+}
+
+// instructions renders the request's prompt template, or the built-in instructions
+func (r Request) instructions() (string, error) {
+	t := r.Prompt
+	if t == nil {
+		t = defaultPrompt
+	}
+
+	var sb strings.Builder
+	data := PromptData{Project: r.Project, Company: r.Company, Language: r.Language, Commits: r.Commits}
+	if err := t.tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt template %s: %w", t.Name, err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// buildPrompt combines the instructions with the commit list and the output format
+func buildPrompt(req Request) (string, error) {
+	instructions, err := req.instructions()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(instructions)
+	sb.WriteString("\n\n")
+	sb.WriteString(fmt.Sprintf("Project: %s\n", req.Project))
+	if req.Company != "" {
+		sb.WriteString(fmt.Sprintf("Company: %s\n", req.Company))
+	}
+	sb.WriteString("\nCommits:\n")
+
+	for _, c := range req.Commits {
+		files := strings.Join(c.FilesChanged, ", ")
+		if len(files) > 100 {
+			files = files[:100] + "..."
+		}
+		sb.WriteString(fmt.Sprintf("- %s: %s (branch: %s, files: %s, lines: +%d/-%d)\n",
+			shortHash(c.Hash), c.Message, c.Branch, files, c.Insertions, c.Deletions))
+	}
+
+	sb.WriteString("\nList the hashes of the commits each task covers, exactly as listed above.\n")
+	sb.WriteString("Every commit should belong to exactly one task.\n")
+	sb.WriteString(languageInstruction(req.Language))
+	sb.WriteString(outputContract)
+
+	return sb.String(), nil
+}
+
+// languageInstruction asks for task descriptions in the given language
+func languageInstruction(language string) string {
+	if language == "" {
+		return ""
+	}
+	return fmt.Sprintf("Write the task descriptions in %s.\n", language)
+}
+
+// completeRequest builds the prompt of req and sends it through run
+func completeRequest(ctx context.Context, run runFunc, req Request) ([]TaskResult, error) {
+	prompt, err := buildPrompt(req)
+	if err != nil {
+		return nil, err
+	}
+	return complete(ctx, run, prompt)
+}
+
+// shortHash abbreviates a commit hash the way it is shown to agents
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
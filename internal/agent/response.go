@@ -0,0 +1,194 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// outputContract is appended to every prompt and describes the JSON the agent must return
+const outputContract = `
+Output format: a single JSON object, with no other text before or after it:
+{"tasks": [{"description": "...", "hours": 1.5, "commits": ["1a2b3c4d"], "category": "feature"}]}
+
+- description: one line starting with a verb (required)
+- hours: estimated time, a multiple of 0.5 (required, at least 0.5)
+- commits: hashes of the commits covered by the task (required, at least one)
+- category: one of feature, fix, refactor, docs, test, chore, other
+
+Example:
+{"tasks": [
+  {"description": "Implemented user authentication system", "hours": 2.0, "commits": ["1a2b3c4d", "5e6f7a8b"], "category": "feature"},
+  {"description": "Fixed login button styling", "hours": 0.5, "commits": ["9c0d1e2f"], "category": "fix"}
+]}
+`
+
+// Categories lists the task categories accepted in agent responses
+var Categories = []string{"feature", "fix", "refactor", "docs", "test", "chore", "other"}
+
+type jsonResponse struct {
+	Tasks []jsonTask `json:"tasks"`
+}
+
+type jsonTask struct {
+	Description string   `json:"description"`
+	Hours       float64  `json:"hours"`
+	Commits     []string `json:"commits"`
+	Category    string   `json:"category"`
+}
+
+// complete sends the prompt through run and parses the JSON response. An invalid
+// response is retried once with a corrective prompt; if that fails too, the
+// free-form line parser is used as a last resort.
+func complete(ctx context.Context, run runFunc, prompt string) ([]TaskResult, error) {
+	output, err := run(ctx, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, parseErr := parseResponse(output)
+	if parseErr == nil {
+		return tasks, nil
+	}
+
+	retry, err := run(ctx, correctivePrompt(prompt, output, parseErr))
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, retryErr := parseResponse(retry)
+	if retryErr == nil {
+		return tasks, nil
+	}
+
+	if tasks := parseLines(retry); len(tasks) > 0 {
+		return tasks, nil
+	}
+	if tasks := parseLines(output); len(tasks) > 0 {
+		return tasks, nil
+	}
+	return nil, fmt.Errorf("invalid agent response: %w", retryErr)
+}
+
+func correctivePrompt(prompt, output string, err error) string {
+	var sb strings.Builder
+
+	sb.WriteString(prompt)
+	sb.WriteString("\nYour previous answer could not be used:\n")
+	sb.WriteString(err.Error())
+	sb.WriteString("\n\nPrevious answer:\n")
+	sb.WriteString(output)
+	sb.WriteString("\n\nAnswer again with ONLY the JSON object described above.\n")
+
+	return sb.String()
+}
+
+// parseResponse extracts the JSON object from an agent's output and validates it
+func parseResponse(response string) ([]TaskResult, error) {
+	block, ok := extractJSON(response)
+	if !ok {
+		return nil, fmt.Errorf("no JSON object found in response")
+	}
+
+	var parsed jsonResponse
+	if err := json.Unmarshal([]byte(block), &parsed); err != nil {
+		return nil, fmt.Errorf("malformed JSON: %w", err)
+	}
+
+	if len(parsed.Tasks) == 0 {
+		return nil, fmt.Errorf("response contains no tasks")
+	}
+
+	var tasks []TaskResult
+	for i, t := range parsed.Tasks {
+		description := strings.TrimSpace(t.Description)
+		if description == "" {
+			return nil, fmt.Errorf("task %d has no description", i+1)
+		}
+		if t.Hours <= 0 {
+			return nil, fmt.Errorf("task %d (%s) has no hours estimate", i+1, description)
+		}
+		if len(t.Commits) == 0 {
+			return nil, fmt.Errorf("task %d (%s) lists no commits", i+1, description)
+		}
+
+		category := strings.ToLower(strings.TrimSpace(t.Category))
+		if !validCategory(category) {
+			category = "other"
+		}
+
+		var hashes []string
+		for _, h := range t.Commits {
+			if h = strings.TrimSpace(h); h != "" {
+				hashes = append(hashes, h)
+			}
+		}
+
+		tasks = append(tasks, TaskResult{
+			Description:    description,
+			EstimatedHours: roundToHalfHour(t.Hours),
+			CommitHashes:   hashes,
+			Category:       category,
+		})
+	}
+
+	return tasks, nil
+}
+
+// extractJSON finds the first balanced JSON object in s that contains a "tasks" key,
+// ignoring any surrounding prose or markdown code fences
+func extractJSON(s string) (string, bool) {
+	for start := strings.IndexByte(s, '{'); start >= 0; {
+		if end := matchBrace(s, start); end > 0 {
+			candidate := s[start : end+1]
+			if strings.Contains(candidate, `"tasks"`) && json.Valid([]byte(candidate)) {
+				return candidate, true
+			}
+		}
+
+		next := strings.IndexByte(s[start+1:], '{')
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return "", false
+}
+
+// matchBrace returns the index of the brace closing the one at start, or -1
+func matchBrace(s string, start int) int {
+	depth := 0
+	inString := false
+	escaped := false
+
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func validCategory(category string) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,223 @@
+package agent
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchBrace(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		start int
+		want  int
+	}{
+		{name: "flat object", s: `{"a": 1}`, want: 7},
+		{name: "nested object", s: `{"a": {"b": 1}} tail`, want: 14},
+		{name: "braces inside strings", s: `{"a": "}{"} x`, want: 10},
+		{name: "escaped quote inside string", s: `{"a": "say \"}\""}`, want: 17},
+		{name: "starts after prose", s: `see {"a": 1}`, start: 4, want: 11},
+		{name: "unbalanced", s: `{"a": {"b": 1}`, want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchBrace(tt.s, tt.start); got != tt.want {
+				t.Errorf("matchBrace(%q, %d) = %d, want %d", tt.s, tt.start, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractJSON(t *testing.T) {
+	const object = `{"tasks": [{"description": "Fixed {braces} in \"names\"", "hours": 1, "commits": ["abc"]}]}`
+
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOK bool
+	}{
+		{name: "bare object", input: object, want: object, wantOK: true},
+		{name: "wrapped in prose", input: "Here are the tasks:\n" + object + "\nLet me know if you need more.", want: object, wantOK: true},
+		{name: "code fence", input: "```json\n" + object + "\n```", want: object, wantOK: true},
+		{name: "skips objects without tasks", input: `{"note": "draft"} then ` + object, want: object, wantOK: true},
+		{name: "skips invalid JSON", input: `{"tasks": oops} ` + object, want: object, wantOK: true},
+		{name: "no object", input: "I could not find any commits.", wantOK: false},
+		{name: "truncated object", input: `{"tasks": [{"description": "Fixed`, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := extractJSON(tt.input)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("extractJSON() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []TaskResult
+		wantErr string
+	}{
+		{
+			name:  "valid response in a code fence",
+			input: "```json\n" + `{"tasks": [{"description": " Added login ", "hours": 1.3, "commits": ["abc", " def ", ""], "category": "Feature"}]}` + "\n```",
+			want:  []TaskResult{{Description: "Added login", EstimatedHours: 1.5, CommitHashes: []string{"abc", "def"}, Category: "feature"}},
+		},
+		{
+			name:  "unknown category becomes other",
+			input: `{"tasks": [{"description": "Tidied up", "hours": 0.5, "commits": ["abc"], "category": "cleanup"}]}`,
+			want:  []TaskResult{{Description: "Tidied up", EstimatedHours: 0.5, CommitHashes: []string{"abc"}, Category: "other"}},
+		},
+		{
+			name:  "braces inside strings",
+			input: `Result: {"tasks": [{"description": "Escaped } and { in \"templates\"", "hours": 2, "commits": ["abc"], "category": "fix"}]}`,
+			want:  []TaskResult{{Description: `Escaped } and { in "templates"`, EstimatedHours: 2, CommitHashes: []string{"abc"}, Category: "fix"}},
+		},
+		{
+			name:    "missing hours",
+			input:   `{"tasks": [{"description": "Added login", "commits": ["abc"]}]}`,
+			wantErr: "has no hours estimate",
+		},
+		{
+			name:    "missing commits",
+			input:   `{"tasks": [{"description": "Added login", "hours": 1}]}`,
+			wantErr: "lists no commits",
+		},
+		{
+			name:    "missing description",
+			input:   `{"tasks": [{"description": " ", "hours": 1, "commits": ["abc"]}]}`,
+			wantErr: "has no description",
+		},
+		{
+			name:    "no tasks",
+			input:   `{"tasks": []}`,
+			wantErr: "no tasks",
+		},
+		{
+			name:    "no JSON",
+			input:   "- [1.0h] Added login (commits: abc)",
+			wantErr: "no JSON object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseResponse(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseResponse() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []TaskResult
+	}{
+		{
+			name:  "bullets with hours and commits",
+			input: "Here are the tasks:\n- [1.5h] Added login (commits: abc, def)\n* [0.2h] Fixed typo (commits: 123)",
+			want: []TaskResult{
+				{Description: "Added login", EstimatedHours: 1.5, CommitHashes: []string{"abc", "def"}},
+				{Description: "Fixed typo", EstimatedHours: 0.5, CommitHashes: []string{"123"}},
+			},
+		},
+		{
+			name:  "hours line without bullet",
+			input: "[2h] Reworked the parser",
+			want:  []TaskResult{{Description: "Reworked the parser", EstimatedHours: 2}},
+		},
+		{
+			name:  "bullet without hours uses the default",
+			input: "- Updated the docs",
+			want:  []TaskResult{{Description: "Updated the docs", EstimatedHours: 0.5}},
+		},
+		{
+			name:  "prose only",
+			input: "I could not summarize these commits.\nSorry!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLines(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLines() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	const valid = `{"tasks": [{"description": "Added login", "hours": 1, "commits": ["abc"], "category": "feature"}]}`
+
+	tests := []struct {
+		name    string
+		outputs []string // answers to the first and the corrective prompt
+		want    []TaskResult
+		wantErr bool
+	}{
+		{
+			name:    "valid first answer",
+			outputs: []string{valid},
+			want:    []TaskResult{{Description: "Added login", EstimatedHours: 1, CommitHashes: []string{"abc"}, Category: "feature"}},
+		},
+		{
+			name:    "corrected after an invalid answer",
+			outputs: []string{`{"tasks": [{"description": "Added login"}]}`, valid},
+			want:    []TaskResult{{Description: "Added login", EstimatedHours: 1, CommitHashes: []string{"abc"}, Category: "feature"}},
+		},
+		{
+			name:    "falls back to lines",
+			outputs: []string{"- [1.0h] Added login (commits: abc)", "still no JSON"},
+			want:    []TaskResult{{Description: "Added login", EstimatedHours: 1, CommitHashes: []string{"abc"}}},
+		},
+		{
+			name:    "nothing usable",
+			outputs: []string{"no idea", "still no idea"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var prompts []string
+			run := func(ctx context.Context, prompt string) (string, error) {
+				prompts = append(prompts, prompt)
+				return tt.outputs[len(prompts)-1], nil
+			}
+
+			got, err := complete(context.Background(), run, "summarize")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("complete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("complete() = %+v, want %+v", got, tt.want)
+			}
+			if len(prompts) != len(tt.outputs) {
+				t.Errorf("agent called %d times, want %d", len(prompts), len(tt.outputs))
+			}
+			if len(prompts) > 1 && !strings.Contains(prompts[1], "could not be used") {
+				t.Errorf("second prompt is not corrective: %q", prompts[1])
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"time"
+
+	"github.com/emilianohg/anchorman/internal/config"
+)
+
+const (
+	defaultTimeout = 10 * time.Minute
+	defaultRetries = 2
+	defaultBackoff = 2 * time.Second
+)
+
+// runFunc sends a prompt to the agent's backend and returns its raw answer
+type runFunc func(ctx context.Context, prompt string) (string, error)
+
+// callPolicy limits how long a single agent call may take and how often
+// transient failures are retried
+type callPolicy struct {
+	name    string
+	timeout time.Duration // per attempt; zero = no limit
+	retries int
+	backoff time.Duration // delay before the first retry, doubled after each one
+}
+
+func newCallPolicy(name string, ac config.AgentConfig) (callPolicy, error) {
+	policy := callPolicy{
+		name:    name,
+		timeout: defaultTimeout,
+		retries: defaultRetries,
+		backoff: defaultBackoff,
+	}
+
+	if ac.Timeout != "" {
+		timeout, err := ac.TimeoutDuration()
+		if err != nil {
+			return callPolicy{}, fmt.Errorf("agent %s: %w", name, err)
+		}
+		policy.timeout = timeout
+	}
+
+	if ac.Retries != nil {
+		if *ac.Retries < 0 {
+			return callPolicy{}, fmt.Errorf("agent %s: retries must not be negative", name)
+		}
+		policy.retries = *ac.Retries
+	}
+
+	return policy, nil
+}
+
+// transientError marks a failure that may succeed when retried,
+// such as a rate limit, a server error or a timeout
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+func transient(err error) error {
+	return &transientError{err: err}
+}
+
+// overloadPattern matches the rate limit and overload messages AI CLIs print
+var overloadPattern = regexp.MustCompile(`(?i)rate.?limit|too many requests|overloaded|temporarily unavailable|\b(429|503|529)\b`)
+
+// commandError wraps the error of a CLI run. Only runs killed by a signal and
+// exits that report a rate limit or overload are transient; other non-zero exits,
+// such as bad arguments or a missing login, fail right away. Timeouts are
+// handled by the call policy.
+func commandError(name string, err error, stderr string) error {
+	wrapped := fmt.Errorf("%s failed: %w\nstderr: %s", name, err, stderr)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && (exitErr.ExitCode() == -1 || overloadPattern.MatchString(stderr)) {
+		return transient(wrapped)
+	}
+	return wrapped
+}
+
+// wrap applies the timeout to each call of run and retries transient failures
+// with exponential backoff. Cancelling ctx stops immediately.
+func (p callPolicy) wrap(run runFunc) runFunc {
+	return func(ctx context.Context, prompt string) (string, error) {
+		delay := p.backoff
+		for attempt := 1; ; attempt++ {
+			output, err := p.attempt(ctx, run, prompt)
+			if err == nil {
+				return output, nil
+			}
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+
+			var te *transientError
+			if !errors.As(err, &te) {
+				return "", err
+			}
+			if attempt > p.retries {
+				if attempt > 1 {
+					return "", fmt.Errorf("%w (gave up after %d attempts)", err, attempt)
+				}
+				return "", err
+			}
+
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+	}
+}
+
+func (p callPolicy) attempt(ctx context.Context, run runFunc, prompt string) (string, error) {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	output, err := run(ctx, prompt)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", transient(fmt.Errorf("%s timed out after %s", p.name, p.timeout))
+	}
+	return output, err
+}
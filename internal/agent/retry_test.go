@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestCommandError(t *testing.T) {
+	tests := []struct {
+		name      string
+		script    string
+		transient bool
+	}{
+		{name: "bad arguments", script: "echo 'unknown flag: --foo' >&2; exit 2", transient: false},
+		{name: "not logged in", script: "echo 'Please run login first' >&2; exit 1", transient: false},
+		{name: "rate limit", script: "echo 'Error: rate limit exceeded' >&2; exit 1", transient: true},
+		{name: "too many requests", script: "echo 'HTTP 429 Too Many Requests' >&2; exit 1", transient: true},
+		{name: "overloaded", script: "echo 'API Error: 529 {\"type\":\"overloaded_error\"}' >&2; exit 1", transient: true},
+		{name: "killed by a signal", script: "kill -9 $$", transient: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := exec.Command("sh", "-c", tt.script).Output()
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("expected an exit error, got %v", err)
+			}
+
+			err = commandError("test", err, string(exitErr.Stderr))
+			var te *transientError
+			if got := errors.As(err, &te); got != tt.transient {
+				t.Errorf("transient = %v, want %v (err: %v)", got, tt.transient, err)
+			}
+		})
+	}
+}
+
+func TestCommandErrorNotStarted(t *testing.T) {
+	_, err := exec.Command("anchorman-no-such-command").Output()
+	err = commandError("test", err, "")
+
+	var te *transientError
+	if errors.As(err, &te) {
+		t.Errorf("a missing command should not be transient: %v", err)
+	}
+}
+
+func TestCallPolicyWrap(t *testing.T) {
+	permanent := errors.New("bad request")
+
+	tests := []struct {
+		name     string
+		errs     []error // returned by each attempt; nil = success
+		retries  int
+		attempts int
+		wantErr  bool
+	}{
+		{name: "success", errs: []error{nil}, retries: 2, attempts: 1},
+		{name: "transient then success", errs: []error{transient(errors.New("busy")), nil}, retries: 2, attempts: 2},
+		{name: "permanent is not retried", errs: []error{permanent}, retries: 2, attempts: 1, wantErr: true},
+		{name: "gives up after the retries", errs: []error{transient(errors.New("busy")), transient(errors.New("busy"))}, retries: 1, attempts: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			run := func(ctx context.Context, prompt string) (string, error) {
+				err := tt.errs[attempts]
+				attempts++
+				if err != nil {
+					return "", err
+				}
+				return "ok", nil
+			}
+
+			policy := callPolicy{name: "test", retries: tt.retries, backoff: time.Millisecond}
+			_, err := policy.wrap(run)(context.Background(), "prompt")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestCallPolicyTimeoutIsRetried(t *testing.T) {
+	attempts := 0
+	run := func(ctx context.Context, prompt string) (string, error) {
+		attempts++
+		if attempts == 1 {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return "ok", nil
+	}
+
+	policy := callPolicy{name: "test", timeout: 10 * time.Millisecond, retries: 1, backoff: time.Millisecond}
+	output, err := policy.wrap(run)(context.Background(), "prompt")
+	if err != nil || output != "ok" {
+		t.Fatalf("wrap() = %q, %v, want ok after a retry", output, err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
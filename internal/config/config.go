@@ -1,24 +1,97 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
 
 type Config struct {
 	DefaultAgent  string   `toml:"default_agent"`
+	Concurrency   int      `toml:"concurrency"` // projects processed in parallel
 	ReportsOutput string   `toml:"reports_output"`
+	ReportFormat  string   `toml:"report_format"` // markdown, html, csv, json or text
 	ScanPaths     []string `toml:"scan_paths"`
+	WeekStart     string   `toml:"week_start"` // first day of the week, e.g. "monday" or "sunday"
+	Timezone      string   `toml:"timezone"`   // IANA name used to assign commits and tasks to days; empty = system local time
+
+	// Prompt size limits; larger batches are split into chunks
+	MaxCommitsPerPrompt int  `toml:"max_commits_per_prompt"` // 0 = no limit
+	MaxPromptChars      int  `toml:"max_prompt_chars"`       // approximate size of a prompt's commit list; 0 = no limit
+	MergeChunks         bool `toml:"merge_chunks"`           // deduplicate tasks across chunks with an extra agent call
+
+	// Prompt template name in ~/.anchorman/prompts or a file path; empty = built-in prompt
+	PromptTemplate string `toml:"prompt_template,omitempty"`
+
+	// Per-company settings, keyed by company name
+	Companies map[string]CompanyConfig `toml:"companies,omitempty"`
+
+	// Per-project settings, keyed by project name
+	Projects map[string]ProjectConfig `toml:"projects,omitempty"`
+
+	// Named agents, keyed by the name used in default_agent or --agent
+	Agents map[string]AgentConfig `toml:"agents,omitempty"`
+}
+
+// AgentConfig configures a named agent
+type AgentConfig struct {
+	Type      string `toml:"type,omitempty"`        // codex, claude, heuristic, anthropic, openai or command; defaults to the agent's name
+	BaseURL   string `toml:"base_url,omitempty"`    // API endpoint, e.g. http://localhost:11434/v1 for a local server
+	Model     string `toml:"model,omitempty"`       // model name sent to the API
+	APIKeyEnv string `toml:"api_key_env,omitempty"` // environment variable holding the API key
+	MaxTokens int    `toml:"max_tokens,omitempty"`  // response token limit (anthropic)
+
+	// command agents
+	Command     string   `toml:"command,omitempty"`      // executable to run
+	Args        []string `toml:"args,omitempty"`         // arguments; {prompt} and {prompt_file} are replaced
+	PromptInput string   `toml:"prompt_input,omitempty"` // stdin (default), arg or file
+
+	// all agents
+	Timeout string `toml:"timeout,omitempty"` // per call, e.g. "90s" or "5m"; empty = 10m, "0" = no limit
+	Retries *int   `toml:"retries,omitempty"` // retries of failed calls; nil = 2
+}
+
+// TimeoutDuration parses the agent's timeout, returning zero when none is set
+func (a AgentConfig) TimeoutDuration() (time.Duration, error) {
+	if a.Timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(a.Timeout)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid agent timeout %q (expected a duration like 90s or 5m)", a.Timeout)
+	}
+	return d, nil
+}
+
+// CompanyConfig holds settings that apply to a single company
+type CompanyConfig struct {
+	ReportTemplate string `toml:"report_template,omitempty"` // template name in ~/.anchorman/templates or a file path
+	ReportOrder    string `toml:"report_order,omitempty"`    // project order: name, hours or manual
+	PromptTemplate string `toml:"prompt_template,omitempty"` // prompt template for the company's projects
+	Locale         string `toml:"locale,omitempty"`          // report and task language: en, es or pt
+}
+
+// ProjectConfig holds settings that apply to a single project
+type ProjectConfig struct {
+	PromptTemplate string `toml:"prompt_template,omitempty"` // prompt template for the project
 }
 
 func DefaultConfig() *Config {
 	homeDir, _ := os.UserHomeDir()
 	return &Config{
 		DefaultAgent:  "codex",
+		Concurrency:   3,
 		ReportsOutput: filepath.Join(homeDir, "Documents", "reports"),
+		ReportFormat:  "markdown",
 		ScanPaths:     []string{filepath.Join(homeDir, "Projects")},
+		WeekStart:     "monday",
+
+		MaxCommitsPerPrompt: 50,
+		MaxPromptChars:      30000,
 	}
 }
 
@@ -54,6 +127,22 @@ func ErrorLogPath() (string, error) {
 	return filepath.Join(dir, "errors.log"), nil
 }
 
+func TemplatesDir() (string, error) {
+	dir, err := AnchormanDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+func PromptsDir() (string, error) {
+	dir, err := AnchormanDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "prompts"), nil
+}
+
 func EnsureDirectories() error {
 	dir, err := AnchormanDir()
 	if err != nil {
@@ -104,6 +193,13 @@ func Load() (*Config, error) {
 		cfg.ScanPaths[i] = expandPath(p)
 	}
 
+	if _, err := cfg.Location(); err != nil {
+		return nil, err
+	}
+	if _, err := cfg.FirstWeekday(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
@@ -131,6 +227,71 @@ func expandPath(path string) string {
 	return path
 }
 
+// Location returns the configured reporting time zone, or local time when unset
+func (c *Config) Location() (*time.Location, error) {
+	if c.Timezone == "" || strings.EqualFold(c.Timezone, "local") {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q in config: %w", c.Timezone, err)
+	}
+	return loc, nil
+}
+
+// FirstWeekday returns the configured first day of the week, Monday by default
+func (c *Config) FirstWeekday() (time.Weekday, error) {
+	if c.WeekStart == "" {
+		return time.Monday, nil
+	}
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(c.WeekStart, d.String()) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid week_start %q in config (expected a weekday like monday or sunday)", c.WeekStart)
+}
+
+// Company returns the settings for the named company, matching the name case-insensitively
+func (c *Config) Company(name string) CompanyConfig {
+	if cc, ok := c.Companies[name]; ok {
+		return cc
+	}
+	for key, cc := range c.Companies {
+		if strings.EqualFold(key, name) {
+			return cc
+		}
+	}
+	return CompanyConfig{}
+}
+
+// Project returns the settings for the named project, matching the name case-insensitively
+func (c *Config) Project(name string) ProjectConfig {
+	if pc, ok := c.Projects[name]; ok {
+		return pc
+	}
+	for key, pc := range c.Projects {
+		if strings.EqualFold(key, name) {
+			return pc
+		}
+	}
+	return ProjectConfig{}
+}
+
+// PromptTemplateFor returns the prompt template configured for a project: its own,
+// else its company's, else the global one. Empty means the built-in prompt.
+func (c *Config) PromptTemplateFor(project, company string) string {
+	if t := c.Project(project).PromptTemplate; t != "" {
+		return t
+	}
+	if company != "" {
+		if t := c.Company(company).PromptTemplate; t != "" {
+			return t
+		}
+	}
+	return c.PromptTemplate
+}
+
 // IsPathTracked checks if a given path is under one of the configured scan paths
 func (c *Config) IsPathTracked(repoPath string) bool {
 	absRepoPath, err := filepath.Abs(repoPath)
@@ -161,3 +322,13 @@ func (c *Config) IsPathTracked(repoPath string) bool {
 
 	return false
 }
+
+// Agent returns the settings of the named agent. Agents without a table in the
+// config get an empty AgentConfig whose type is their name.
+func (c *Config) Agent(name string) AgentConfig {
+	ac := c.Agents[name]
+	if ac.Type == "" {
+		ac.Type = name
+	}
+	return ac
+}
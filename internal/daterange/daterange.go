@@ -0,0 +1,203 @@
+package daterange
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/emilianohg/anchorman/internal/config"
+)
+
+// Preset is a named date range relative to today
+type Preset int
+
+const (
+	Today Preset = iota
+	ThisWeek
+	LastWeek
+	ThisMonth
+	LastMonth
+	ThisQuarter
+	LastQuarter
+	YearToDate
+	Last7Days
+	Last30Days
+	Custom // from/to entered by the user
+)
+
+var presetInfo = map[Preset]struct {
+	label string
+	slug  string
+}{
+	Today:       {"Today", "today"},
+	ThisWeek:    {"This Week", "this-week"},
+	LastWeek:    {"Last Week", "last-week"},
+	ThisMonth:   {"This Month", "this-month"},
+	LastMonth:   {"Last Month", "last-month"},
+	ThisQuarter: {"This Quarter", "this-quarter"},
+	LastQuarter: {"Last Quarter", "last-quarter"},
+	YearToDate:  {"Year to Date", "year-to-date"},
+	Last7Days:   {"Last 7 Days", "last-7-days"},
+	Last30Days:  {"Last 30 Days", "last-30-days"},
+	Custom:      {"Custom Range...", "custom"},
+}
+
+// DateLayout is the format used for typed and command-line dates
+const DateLayout = "2006-01-02"
+
+func (p Preset) String() string {
+	return presetInfo[p].label
+}
+
+// Slug returns the command-line name of the preset, e.g. "last-month"
+func (p Preset) Slug() string {
+	return presetInfo[p].slug
+}
+
+// ParsePreset finds a preset by its command-line name
+func ParsePreset(slug string) (Preset, error) {
+	for p, info := range presetInfo {
+		if info.slug == slug && p != Custom {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown date range: %s", slug)
+}
+
+// Calendar holds the settings that decide where days and weeks begin
+type Calendar struct {
+	WeekStart time.Weekday
+	Location  *time.Location
+}
+
+// NewCalendar builds a calendar from the week_start and timezone settings
+func NewCalendar(cfg *config.Config) Calendar {
+	loc, err := cfg.Location()
+	if err != nil {
+		loc = time.Local
+	}
+	weekStart, err := cfg.FirstWeekday()
+	if err != nil {
+		weekStart = time.Monday
+	}
+	return Calendar{WeekStart: weekStart, Location: loc}
+}
+
+// DefaultCalendar uses Monday-start weeks and the local time zone
+func DefaultCalendar() Calendar {
+	return Calendar{WeekStart: time.Monday, Location: time.Local}
+}
+
+func (c Calendar) location() *time.Location {
+	if c.Location == nil {
+		return time.Local
+	}
+	return c.Location
+}
+
+// In converts t to the calendar's time zone
+func (c Calendar) In(t time.Time) time.Time {
+	return t.In(c.location())
+}
+
+// Day returns the start of the day containing t, in the calendar's time zone
+func (c Calendar) Day(t time.Time) time.Time {
+	return startOfDay(t.In(c.location()))
+}
+
+// EndOfDay returns the last second of the day containing t, in the calendar's time zone
+func (c Calendar) EndOfDay(t time.Time) time.Time {
+	return endOfDay(t.In(c.location()))
+}
+
+// Week returns the first and last second of the week containing t
+func (c Calendar) Week(t time.Time) (time.Time, time.Time) {
+	start := c.weekStart(c.Day(t))
+	return start, endOfDay(start.AddDate(0, 0, 6))
+}
+
+// Range returns the first and last second of the preset relative to today.
+// Ranges that include the current period end today. Custom returns today.
+func (c Calendar) Range(p Preset) (time.Time, time.Time) {
+	return c.rangeAt(p, time.Now())
+}
+
+// rangeAt returns the range of the preset relative to the day containing now
+func (c Calendar) rangeAt(p Preset, now time.Time) (time.Time, time.Time) {
+	today := c.Day(now)
+	endOfToday := endOfDay(today)
+
+	switch p {
+	case ThisWeek:
+		return c.weekStart(today), endOfToday
+	case LastWeek:
+		start := c.weekStart(today).AddDate(0, 0, -7)
+		return start, endOfDay(start.AddDate(0, 0, 6))
+	case ThisMonth:
+		return monthStart(today), endOfToday
+	case LastMonth:
+		start := monthStart(today).AddDate(0, -1, 0)
+		return start, endOfDay(monthStart(today).AddDate(0, 0, -1))
+	case ThisQuarter:
+		return quarterStart(today), endOfToday
+	case LastQuarter:
+		start := quarterStart(today).AddDate(0, -3, 0)
+		return start, endOfDay(quarterStart(today).AddDate(0, 0, -1))
+	case YearToDate:
+		return time.Date(today.Year(), 1, 1, 0, 0, 0, 0, today.Location()), endOfToday
+	case Last7Days:
+		return today.AddDate(0, 0, -6), endOfToday
+	case Last30Days:
+		return today.AddDate(0, 0, -29), endOfToday
+	}
+	return today, endOfToday
+}
+
+// Parse validates a typed from/to pair of YYYY-MM-DD dates and returns
+// the first second of from and the last second of to
+func (c Calendar) Parse(from, to string) (time.Time, time.Time, error) {
+	start, err := c.ParseDate(from)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start date %q (expected YYYY-MM-DD)", from)
+	}
+
+	end, err := c.ParseDate(to)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end date %q (expected YYYY-MM-DD)", to)
+	}
+
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("end date %s is before start date %s", to, from)
+	}
+
+	return start, endOfDay(end), nil
+}
+
+// ParseDate parses a YYYY-MM-DD date as the start of that day in the calendar's time zone
+func (c Calendar) ParseDate(s string) (time.Time, error) {
+	return time.ParseInLocation(DateLayout, s, c.location())
+}
+
+// weekStart returns the first day of the week containing day
+func (c Calendar) weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) - int(c.WeekStart) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// endOfDay returns the last second of the day containing t. Days are counted on
+// the calendar, so days with a daylight saving change end at 23:59:59 too.
+func endOfDay(t time.Time) time.Time {
+	return startOfDay(t).AddDate(0, 0, 1).Add(-time.Second)
+}
+
+func monthStart(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+}
+
+func quarterStart(day time.Time) time.Time {
+	month := (int(day.Month())-1)/3*3 + 1
+	return time.Date(day.Year(), time.Month(month), 1, 0, 0, 0, 0, day.Location())
+}
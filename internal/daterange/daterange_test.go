@@ -0,0 +1,207 @@
+package daterange
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // the DST tests need America/New_York on every system
+
+	"github.com/emilianohg/anchorman/internal/config"
+)
+
+const timeLayout = "2006-01-02 15:04:05 MST"
+
+func date(t *testing.T, loc *time.Location, s string) time.Time {
+	t.Helper()
+	d, err := time.ParseInLocation(DateLayout, s, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func newYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestRange(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 5, 14, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		preset    Preset
+		weekStart time.Weekday
+		from, to  string
+	}{
+		{Today, time.Monday, "2025-05-14", "2025-05-14"},
+		{ThisWeek, time.Monday, "2025-05-12", "2025-05-14"},
+		{ThisWeek, time.Sunday, "2025-05-11", "2025-05-14"},
+		{LastWeek, time.Monday, "2025-05-05", "2025-05-11"},
+		{LastWeek, time.Sunday, "2025-05-04", "2025-05-10"},
+		{ThisMonth, time.Monday, "2025-05-01", "2025-05-14"},
+		{LastMonth, time.Monday, "2025-04-01", "2025-04-30"},
+		{ThisQuarter, time.Monday, "2025-04-01", "2025-05-14"},
+		{LastQuarter, time.Monday, "2025-01-01", "2025-03-31"},
+		{YearToDate, time.Monday, "2025-01-01", "2025-05-14"},
+		{Last7Days, time.Monday, "2025-05-08", "2025-05-14"},
+		{Last30Days, time.Monday, "2025-04-15", "2025-05-14"},
+		{Custom, time.Monday, "2025-05-14", "2025-05-14"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.preset.Slug()+"/"+tt.weekStart.String(), func(t *testing.T) {
+			cal := Calendar{WeekStart: tt.weekStart, Location: time.UTC}
+			from, to := cal.rangeAt(tt.preset, now)
+
+			wantFrom := date(t, time.UTC, tt.from)
+			wantTo := date(t, time.UTC, tt.to).Add(24*time.Hour - time.Second)
+			if !from.Equal(wantFrom) || !to.Equal(wantTo) {
+				t.Errorf("rangeAt() = %s - %s, want %s - %s",
+					from.Format(timeLayout), to.Format(timeLayout), wantFrom.Format(timeLayout), wantTo.Format(timeLayout))
+			}
+		})
+	}
+}
+
+func TestRangeAcrossYears(t *testing.T) {
+	cal := Calendar{WeekStart: time.Monday, Location: time.UTC}
+	now := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC) // Thursday
+
+	tests := []struct {
+		preset   Preset
+		from, to string
+	}{
+		{ThisWeek, "2024-12-30", "2025-01-02"},
+		{LastWeek, "2024-12-23", "2024-12-29"},
+		{LastMonth, "2024-12-01", "2024-12-31"},
+		{LastQuarter, "2024-10-01", "2024-12-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.preset.Slug(), func(t *testing.T) {
+			from, to := cal.rangeAt(tt.preset, now)
+			if got := from.Format(DateLayout); got != tt.from {
+				t.Errorf("from = %s, want %s", got, tt.from)
+			}
+			if got := to.Format(DateLayout); got != tt.to {
+				t.Errorf("to = %s, want %s", got, tt.to)
+			}
+		})
+	}
+}
+
+func TestRangeUsesCalendarTimeZone(t *testing.T) {
+	loc := newYork(t)
+	cal := Calendar{WeekStart: time.Monday, Location: loc}
+
+	// Still the evening of May 13 in New York
+	now := time.Date(2025, 5, 14, 2, 0, 0, 0, time.UTC)
+	from, to := cal.rangeAt(Today, now)
+
+	if want := time.Date(2025, 5, 13, 0, 0, 0, 0, loc); !from.Equal(want) {
+		t.Errorf("from = %s, want %s", from.Format(timeLayout), want.Format(timeLayout))
+	}
+	if want := time.Date(2025, 5, 13, 23, 59, 59, 0, loc); !to.Equal(want) {
+		t.Errorf("to = %s, want %s", to.Format(timeLayout), want.Format(timeLayout))
+	}
+}
+
+func TestEndOfDayDST(t *testing.T) {
+	loc := newYork(t)
+
+	tests := []struct {
+		name string
+		day  string
+	}{
+		{name: "spring forward, 23 hours", day: "2025-03-09"},
+		{name: "fall back, 25 hours", day: "2025-11-02"},
+		{name: "regular day", day: "2025-06-15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day := date(t, loc, tt.day)
+			want := time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 59, 0, loc)
+
+			for _, at := range []time.Time{day, day.Add(12 * time.Hour)} {
+				if got := endOfDay(at); !got.Equal(want) {
+					t.Errorf("endOfDay(%s) = %s, want %s", at.Format(timeLayout), got.Format(timeLayout), want.Format(timeLayout))
+				}
+			}
+		})
+	}
+}
+
+func TestWeekDST(t *testing.T) {
+	loc := newYork(t)
+	cal := Calendar{WeekStart: time.Monday, Location: loc}
+
+	// The week of the spring forward change ends on Sunday, March 9
+	from, to := cal.Week(time.Date(2025, 3, 5, 12, 0, 0, 0, loc))
+	if want := time.Date(2025, 3, 3, 0, 0, 0, 0, loc); !from.Equal(want) {
+		t.Errorf("from = %s, want %s", from.Format(timeLayout), want.Format(timeLayout))
+	}
+	if want := time.Date(2025, 3, 9, 23, 59, 59, 0, loc); !to.Equal(want) {
+		t.Errorf("to = %s, want %s", to.Format(timeLayout), want.Format(timeLayout))
+	}
+}
+
+func TestParse(t *testing.T) {
+	loc := newYork(t)
+	cal := Calendar{WeekStart: time.Monday, Location: loc}
+
+	from, to, err := cal.Parse("2025-03-01", "2025-03-09")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 3, 1, 0, 0, 0, 0, loc); !from.Equal(want) {
+		t.Errorf("from = %s, want %s", from.Format(timeLayout), want.Format(timeLayout))
+	}
+	if want := time.Date(2025, 3, 9, 23, 59, 59, 0, loc); !to.Equal(want) {
+		t.Errorf("to = %s, want %s", to.Format(timeLayout), want.Format(timeLayout))
+	}
+
+	for _, tt := range [][2]string{{"2025-03-09", "2025-03-01"}, {"03/01/2025", "2025-03-09"}, {"2025-03-01", "soon"}} {
+		if _, _, err := cal.Parse(tt[0], tt[1]); err == nil {
+			t.Errorf("Parse(%q, %q) should fail", tt[0], tt[1])
+		}
+	}
+}
+
+func TestNewCalendarWeekStart(t *testing.T) {
+	tests := []struct {
+		weekStart string
+		want      time.Weekday
+	}{
+		{"", time.Monday},
+		{"monday", time.Monday},
+		{"Sunday", time.Sunday},
+		{"saturday", time.Saturday},
+		{"someday", time.Monday}, // invalid values fall back to Monday
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.weekStart, func(t *testing.T) {
+			cal := NewCalendar(&config.Config{WeekStart: tt.weekStart, Timezone: "UTC"})
+			if cal.WeekStart != tt.want {
+				t.Errorf("WeekStart = %s, want %s", cal.WeekStart, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePreset(t *testing.T) {
+	for p := Today; p < Custom; p++ {
+		got, err := ParsePreset(p.Slug())
+		if err != nil || got != p {
+			t.Errorf("ParsePreset(%q) = %v, %v, want %v", p.Slug(), got, err, p)
+		}
+	}
+	if _, err := ParsePreset("custom"); err == nil {
+		t.Error("custom should not be accepted on the command line")
+	}
+}
@@ -64,6 +64,30 @@ func OpenAndMigrate() (*sql.DB, error) {
 	return database, nil
 }
 
+// OpenInMemory opens a new, empty in-memory database with all migrations applied.
+// It is independent of the database returned by Open, which makes it suitable for tests.
+func OpenInMemory() (*sql.DB, error) {
+	database, err := sql.Open("sqlite3", "file::memory:?_foreign_keys=on")
+	if err != nil {
+		return nil, err
+	}
+
+	// Every connection to :memory: opens a separate database, so keep a single one
+	database.SetMaxOpenConns(1)
+
+	m, err := getMigrator(database)
+	if err != nil {
+		database.Close()
+		return nil, err
+	}
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		database.Close()
+		return nil, err
+	}
+
+	return database, nil
+}
+
 func Close() error {
 	if db != nil {
 		err := db.Close()
@@ -79,7 +103,7 @@ func GetMigrationStatus() (*MigrationStatus, error) {
 		return nil, fmt.Errorf("database not open")
 	}
 
-	m, err := getMigrator()
+	m, err := getMigrator(db)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +150,7 @@ func RunMigrations() error {
 		return fmt.Errorf("database not open")
 	}
 
-	m, err := getMigrator()
+	m, err := getMigrator(db)
 	if err != nil {
 		return err
 	}
@@ -138,9 +162,9 @@ func RunMigrations() error {
 	return nil
 }
 
-// getMigrator creates a new migrate instance
-func getMigrator() (*migrate.Migrate, error) {
-	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{})
+// getMigrator creates a new migrate instance for database
+func getMigrator(database *sql.DB) (*migrate.Migrate, error) {
+	driver, err := sqlite3.WithInstance(database, &sqlite3.Config{})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,8 @@
+ALTER TABLE projects ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0;
+
+-- Number existing projects by name within their company, starting at 1
+UPDATE projects SET sort_order = (
+    SELECT COUNT(*) FROM projects p
+    WHERE p.company_id IS projects.company_id
+      AND (p.name < projects.name OR (p.name = projects.name AND p.id <= projects.id))
+);
@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN category TEXT NOT NULL DEFAULT 'other';
@@ -0,0 +1,2 @@
+ALTER TABLE raw_commits DROP COLUMN deletions;
+ALTER TABLE raw_commits DROP COLUMN insertions;
@@ -0,0 +1,2 @@
+ALTER TABLE raw_commits ADD COLUMN insertions INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE raw_commits ADD COLUMN deletions INTEGER NOT NULL DEFAULT 0;
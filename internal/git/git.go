@@ -13,6 +13,8 @@ type CommitInfo struct {
 	Author       string
 	Branch       string
 	FilesChanged []string
+	Insertions   int // lines added; binary files are not counted
+	Deletions    int // lines removed
 	CommittedAt  time.Time
 }
 
@@ -73,6 +75,9 @@ func GetCurrentCommit() (*CommitInfo, error) {
 		info.FilesChanged = []string{}
 	}
 
+	// Get lines added and removed; a commit without stats still gets ingested
+	info.Insertions, info.Deletions, _ = getDiffStatsForCommit("HEAD")
+
 	// Get timestamp
 	timestamp, err := runGitCommand("log", "-1", "--format=%ci")
 	if err != nil {
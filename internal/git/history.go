@@ -3,6 +3,7 @@ package git
 import (
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -70,6 +71,9 @@ func GetCommitHistory(opts HistoryOptions) ([]CommitInfo, error) {
 			filesChanged = []string{}
 		}
 
+		// Get lines added and removed; a commit without stats still gets imported
+		insertions, deletions, _ := getDiffStatsForCommit(hash)
+
 		// Get branch for this commit
 		branch, err := getBranchForCommit(hash)
 		if err != nil {
@@ -82,6 +86,8 @@ func GetCommitHistory(opts HistoryOptions) ([]CommitInfo, error) {
 			Author:       author,
 			Branch:       branch,
 			FilesChanged: filesChanged,
+			Insertions:   insertions,
+			Deletions:    deletions,
 			CommittedAt:  committedAt,
 		})
 	}
@@ -109,6 +115,37 @@ func getFilesChangedForCommit(hash string) ([]string, error) {
 	return strings.Split(result, "\n"), nil
 }
 
+// getDiffStatsForCommit sums the lines added and removed by a commit.
+// Binary files, shown as "-" by --numstat, are skipped.
+func getDiffStatsForCommit(hash string) (int, int, error) {
+	cmd := exec.Command("git", "show", "--numstat", "--format=", hash)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	insertions, deletions := 0, 0
+	for _, line := range strings.Split(string(output), "\n") {
+		// Lines look like "12\t3\tpath/to/file"
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		added, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		removed, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		insertions += added
+		deletions += removed
+	}
+
+	return insertions, deletions, nil
+}
+
 func getBranchForCommit(hash string) (string, error) {
 	// Get branches containing this commit
 	cmd := exec.Command("git", "branch", "--contains", hash, "--format=%(refname:short)")
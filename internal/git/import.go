@@ -1,6 +1,7 @@
 package git
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -97,25 +98,37 @@ func Import(opts ImportOptions) (*ImportResult, error) {
 
 		if existing != nil {
 			if opts.Force {
-				// Delete tasks referencing this commit
-				deleted, err := taskRepo.DeleteTasksWithCommit(existing.ID)
+				// Delete tasks referencing this commit and reset it in one
+				// step, so a failure can't leave the commit without its tasks
+				deleted := 0
+				err := repository.WithTx(database, func(tx *sql.Tx) error {
+					var err error
+					deleted, err = taskRepo.DeleteTasksWithCommitTx(tx, existing.ID)
+					if err != nil {
+						return fmt.Errorf("failed to delete tasks for commit %s: %w", commit.Hash[:8], err)
+					}
+
+					// Update commit and mark as unprocessed
+					err = commitRepo.UpdateAndMarkUnprocessedTx(
+						tx,
+						existing.ID,
+						commit.Message,
+						commit.Author,
+						commit.Branch,
+						commit.FilesChanged,
+						commit.Insertions,
+						commit.Deletions,
+						commit.CommittedAt,
+					)
+					if err != nil {
+						return fmt.Errorf("failed to update commit %s: %w", commit.Hash[:8], err)
+					}
+					return nil
+				})
 				if err != nil {
-					return nil, fmt.Errorf("failed to delete tasks for commit %s: %w", commit.Hash[:8], err)
+					return nil, err
 				}
 				result.TasksDeleted += deleted
-
-				// Update commit and mark as unprocessed
-				err = commitRepo.UpdateAndMarkUnprocessed(
-					existing.ID,
-					commit.Message,
-					commit.Author,
-					commit.Branch,
-					commit.FilesChanged,
-					commit.CommittedAt,
-				)
-				if err != nil {
-					return nil, fmt.Errorf("failed to update commit %s: %w", commit.Hash[:8], err)
-				}
 				result.Updated++
 			} else {
 				result.Skipped++
@@ -131,6 +144,8 @@ func Import(opts ImportOptions) (*ImportResult, error) {
 			commit.Author,
 			commit.Branch,
 			commit.FilesChanged,
+			commit.Insertions,
+			commit.Deletions,
 			commit.CommittedAt,
 		)
 		if err != nil {
@@ -87,6 +87,8 @@ func Ingest(verbose bool) (*IngestResult, error) {
 		commitInfo.Author,
 		commitInfo.Branch,
 		commitInfo.FilesChanged,
+		commitInfo.Insertions,
+		commitInfo.Deletions,
 		commitInfo.CommittedAt,
 	)
 	if err != nil {
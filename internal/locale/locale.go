@@ -0,0 +1,199 @@
+package locale
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale holds the labels, date formats and number format of one language.
+// Its exported fields and methods are available to report templates as .Locale.
+type Locale struct {
+	Code     string // en, es or pt
+	Language string // English name of the language, used in agent prompts
+
+	// Report labels
+	Report       string
+	Period       string
+	Generated    string
+	Contributors string
+	Subtotal     string
+	Total        string
+	DailyTotal   string
+	WeeklyTotal  string
+	Week         string
+	GeneratedBy  string
+	Changes      string // lines added and removed
+	Lines        string
+
+	longDate  string // layout of Date, see expand
+	dayLabel  string // layout of Day
+	shortDate string // layout of ShortDate
+	decimal   string // decimal separator
+	thousands string // digit group separator
+
+	months      [12]string // January first
+	shortMonths [12]string
+	weekdays    [7]string // Sunday first, like time.Weekday
+}
+
+// Codes lists the supported locales
+var Codes = []string{"en", "es", "pt"}
+
+var english = &Locale{
+	Code:         "en",
+	Language:     "English",
+	Report:       "Report",
+	Period:       "Period",
+	Generated:    "Generated",
+	Contributors: "Contributors",
+	Subtotal:     "Subtotal",
+	Total:        "Total",
+	DailyTotal:   "Daily total",
+	WeeklyTotal:  "Weekly total",
+	Week:         "Week",
+	GeneratedBy:  "Generated by Anchorman",
+	Changes:      "Changes",
+	Lines:        "lines",
+	longDate:     "{month} {day}, {year}",
+	dayLabel:     "{weekday}, {mon} {day}",
+	shortDate:    "{mon} {day}",
+	decimal:      ".",
+	thousands:    ",",
+	months:       [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	shortMonths:  [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	weekdays:     [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+}
+
+var spanish = &Locale{
+	Code:         "es",
+	Language:     "Spanish",
+	Report:       "Reporte",
+	Period:       "Periodo",
+	Generated:    "Generado",
+	Contributors: "Colaboradores",
+	Subtotal:     "Subtotal",
+	Total:        "Total",
+	DailyTotal:   "Total del día",
+	WeeklyTotal:  "Total de la semana",
+	Week:         "Semana",
+	GeneratedBy:  "Generado por Anchorman",
+	Changes:      "Cambios",
+	Lines:        "líneas",
+	longDate:     "{day} de {month} de {year}",
+	dayLabel:     "{weekday}, {day} {mon}",
+	shortDate:    "{day} {mon}",
+	decimal:      ",",
+	thousands:    ".",
+	months:       [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	shortMonths:  [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"},
+	weekdays:     [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+}
+
+var portuguese = &Locale{
+	Code:         "pt",
+	Language:     "Portuguese",
+	Report:       "Relatório",
+	Period:       "Período",
+	Generated:    "Gerado em",
+	Contributors: "Colaboradores",
+	Subtotal:     "Subtotal",
+	Total:        "Total",
+	DailyTotal:   "Total do dia",
+	WeeklyTotal:  "Total da semana",
+	Week:         "Semana",
+	GeneratedBy:  "Gerado por Anchorman",
+	Changes:      "Alterações",
+	Lines:        "linhas",
+	longDate:     "{day} de {month} de {year}",
+	dayLabel:     "{weekday}, {day} {mon}",
+	shortDate:    "{day} {mon}",
+	decimal:      ",",
+	thousands:    ".",
+	months:       [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+	shortMonths:  [12]string{"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+	weekdays:     [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+}
+
+// Default returns the English locale
+func Default() *Locale {
+	return english
+}
+
+// Parse returns the locale for a code like "es" or "pt-BR"; empty means English
+func Parse(code string) (*Locale, error) {
+	lang, _, _ := strings.Cut(strings.ToLower(code), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+
+	switch lang {
+	case "", "en":
+		return english, nil
+	case "es":
+		return spanish, nil
+	case "pt":
+		return portuguese, nil
+	default:
+		return nil, fmt.Errorf("unknown locale: %s (expected %s)", code, strings.Join(Codes, ", "))
+	}
+}
+
+// expand fills in the {weekday}, {day}, {month}, {mon} and {year} placeholders of
+// a layout, taking the names from the locale's tables
+func (l *Locale) expand(t time.Time, layout string) string {
+	return strings.NewReplacer(
+		"{weekday}", l.weekdays[t.Weekday()],
+		"{day}", fmt.Sprintf("%02d", t.Day()),
+		"{month}", l.months[t.Month()-1],
+		"{mon}", l.shortMonths[t.Month()-1],
+		"{year}", strconv.Itoa(t.Year()),
+	).Replace(layout)
+}
+
+// Date formats a date in full, e.g. "January 02, 2006" or "02 de enero de 2006"
+func (l *Locale) Date(t time.Time) string {
+	return l.expand(t, l.longDate)
+}
+
+// Day formats a date with its weekday, e.g. "Monday, Jan 02" or "lunes, 02 ene"
+func (l *Locale) Day(t time.Time) string {
+	return l.expand(t, l.dayLabel)
+}
+
+// ShortDate formats a day and month, e.g. "Jan 02" or "02 ene"
+func (l *Locale) ShortDate(t time.Time) string {
+	return l.expand(t, l.shortDate)
+}
+
+// Number formats n with the given number of decimals and the locale's decimal separator
+func (l *Locale) Number(n float64, decimals int) string {
+	return strings.Replace(fmt.Sprintf("%.*f", decimals, n), ".", l.decimal, 1)
+}
+
+// Hours formats hours like "1.5h" or "1,5h"
+func (l *Locale) Hours(h float64) string {
+	return l.Number(h, 1) + "h"
+}
+
+// Integer formats n with the locale's digit group separator, e.g. "1,234" or "1.234"
+func (l *Locale) Integer(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+
+	var sb strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteString(l.thousands)
+		}
+		sb.WriteRune(d)
+	}
+	return sign + sb.String()
+}
+
+// Churn formats lines added and removed, e.g. "+1,234 / -56 lines"
+func (l *Locale) Churn(insertions, deletions int) string {
+	return fmt.Sprintf("+%s / -%s %s", l.Integer(insertions), l.Integer(deletions), l.Lines)
+}
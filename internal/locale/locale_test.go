@@ -0,0 +1,132 @@
+package locale
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDatesInEveryMonth(t *testing.T) {
+	months := map[string][12]string{
+		"en": {"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		"es": {"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		"pt": {"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+	}
+	shortMonths := map[string][12]string{
+		"en": {"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		"es": {"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"},
+		"pt": {"jan", "fev", "mar", "abr", "mai", "jun", "jul", "ago", "set", "out", "nov", "dez"},
+	}
+
+	for _, code := range Codes {
+		l, err := Parse(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for m := time.January; m <= time.December; m++ {
+			t.Run(fmt.Sprintf("%s/%s", code, m), func(t *testing.T) {
+				d := time.Date(2025, m, 2, 12, 0, 0, 0, time.UTC)
+				month, short := months[code][m-1], shortMonths[code][m-1]
+
+				wantDate := fmt.Sprintf("02 de %s de 2025", month)
+				wantShort := "02 " + short
+				if code == "en" {
+					wantDate = month + " 02, 2025"
+					wantShort = short + " 02"
+				}
+
+				if got := l.Date(d); got != wantDate {
+					t.Errorf("Date() = %q, want %q", got, wantDate)
+				}
+				if got := l.ShortDate(d); got != wantShort {
+					t.Errorf("ShortDate() = %q, want %q", got, wantShort)
+				}
+			})
+		}
+	}
+}
+
+func TestDay(t *testing.T) {
+	tests := []struct {
+		code string
+		date time.Time
+		want string
+	}{
+		{"en", time.Date(2025, 5, 4, 0, 0, 0, 0, time.UTC), "Sunday, May 04"},
+		{"en", time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC), "Monday, May 05"},
+		{"es", time.Date(2025, 5, 4, 0, 0, 0, 0, time.UTC), "domingo, 04 may"},
+		{"es", time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC), "miércoles, 07 may"},
+		{"es", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), "sábado, 15 mar"},
+		{"pt", time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC), "segunda-feira, 05 mai"},
+		{"pt", time.Date(2025, 5, 9, 0, 0, 0, 0, time.UTC), "sexta-feira, 09 mai"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+"/"+tt.want, func(t *testing.T) {
+			l, err := Parse(tt.code)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := l.Day(tt.date); got != tt.want {
+				t.Errorf("Day() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    string
+		wantErr bool
+	}{
+		{code: "", want: "en"},
+		{code: "en", want: "en"},
+		{code: "es-MX", want: "es"},
+		{code: "pt_BR", want: "pt"},
+		{code: "PT", want: "pt"},
+		{code: "fr", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			l, err := Parse(tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.code, err, tt.wantErr)
+			}
+			if !tt.wantErr && l.Code != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.code, l.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	en, es := Default(), spanish
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"en hours", en.Hours(1.5), "1.5h"},
+		{"es hours", es.Hours(1.5), "1,5h"},
+		{"en number", en.Number(1234.567, 2), "1234.57"},
+		{"es number", es.Number(1234.567, 2), "1234,57"},
+		{"en integer", en.Integer(1234567), "1,234,567"},
+		{"es integer", es.Integer(1234567), "1.234.567"},
+		{"small integer", en.Integer(999), "999"},
+		{"negative integer", en.Integer(-1234), "-1,234"},
+		{"en churn", en.Churn(1234, 56), "+1,234 / -56 lines"},
+		{"es churn", es.Churn(1234, 56), "+1.234 / -56 líneas"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
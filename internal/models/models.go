@@ -37,6 +37,8 @@ type RawCommit struct {
 	Author       string
 	Branch       string
 	FilesChanged []string
+	Insertions   int // lines added, from git show --numstat
+	Deletions    int // lines removed
 	CommittedAt  time.Time
 	Processed    bool
 	CreatedAt    time.Time
@@ -45,6 +47,9 @@ type RawCommit struct {
 	RepoPath string
 }
 
+// CategoryOther is the category of tasks whose kind of work is unknown, e.g. manual tasks
+const CategoryOther = "other"
+
 type Task struct {
 	ID             int64
 	ProjectID      int64
@@ -52,9 +57,12 @@ type Task struct {
 	SourceCommits  []int64
 	TaskDate       time.Time
 	EstimatedHours float64 // 0.5 increments: 0.5, 1.0, 1.5, etc.
+	Category       string  // feature, fix, refactor, docs, test, chore or other
 	CreatedAt      time.Time
 
 	// Joined fields
 	ProjectName string
 	Authors     []string // Derived from source_commits -> raw_commits.author
+	Insertions  int      // Lines added by the source commits
+	Deletions   int      // Lines removed by the source commits
 }
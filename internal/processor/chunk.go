@@ -0,0 +1,56 @@
+package processor
+
+import (
+	"strings"
+
+	"github.com/emilianohg/anchorman/internal/config"
+	"github.com/emilianohg/anchorman/internal/models"
+)
+
+// Chunking limits how much of a batch is sent to the agent in a single prompt
+type Chunking struct {
+	MaxCommits int  // commits per prompt; zero = no limit
+	MaxChars   int  // approximate size of the commit list per prompt; zero = no limit
+	Merge      bool // run a merge pass that deduplicates tasks across chunks
+}
+
+// ChunkingFromConfig returns the prompt size limits set in the config
+func ChunkingFromConfig(cfg *config.Config) Chunking {
+	return Chunking{
+		MaxCommits: cfg.MaxCommitsPerPrompt,
+		MaxChars:   cfg.MaxPromptChars,
+		Merge:      cfg.MergeChunks,
+	}
+}
+
+// promptSize estimates how many characters a commit adds to a prompt
+func promptSize(c models.RawCommit) int {
+	files := len(strings.Join(c.FilesChanged, ", "))
+	return 60 + len(c.Message) + len(c.Branch) + min(files, 100)
+}
+
+// chunk splits commits, which are ordered by date, into consecutive chunks
+// that respect the limits. A single commit larger than MaxChars gets a chunk of its own.
+func (c Chunking) chunk(commits []models.RawCommit) [][]models.RawCommit {
+	var chunks [][]models.RawCommit
+	var current []models.RawCommit
+	size := 0
+
+	for _, commit := range commits {
+		s := promptSize(commit)
+		full := (c.MaxCommits > 0 && len(current) >= c.MaxCommits) ||
+			(c.MaxChars > 0 && size+s > c.MaxChars)
+
+		if full && len(current) > 0 {
+			chunks = append(chunks, current)
+			current, size = nil, 0
+		}
+		current = append(current, commit)
+		size += s
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
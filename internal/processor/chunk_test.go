@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/emilianohg/anchorman/internal/models"
+)
+
+func TestChunk(t *testing.T) {
+	// commit returns a commit that adds size characters to a prompt
+	commit := func(hash string, size int) models.RawCommit {
+		overhead := promptSize(models.RawCommit{})
+		return models.RawCommit{Hash: hash, Message: strings.Repeat("x", size-overhead)}
+	}
+	commits := []models.RawCommit{
+		commit("a", 100), commit("b", 100), commit("c", 300), commit("d", 70), commit("e", 70),
+	}
+
+	tests := []struct {
+		name     string
+		chunking Chunking
+		want     [][]string
+	}{
+		{"no limits", Chunking{}, [][]string{{"a", "b", "c", "d", "e"}}},
+		{"max commits", Chunking{MaxCommits: 2}, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}},
+		{"max chars", Chunking{MaxChars: 200}, [][]string{{"a", "b"}, {"c"}, {"d", "e"}}},
+		{"oversized commit", Chunking{MaxChars: 150}, [][]string{{"a"}, {"b"}, {"c"}, {"d", "e"}}},
+		{"both limits", Chunking{MaxCommits: 1, MaxChars: 1000}, [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]string
+			for _, chunk := range tt.chunking.chunk(commits) {
+				var hashes []string
+				for _, c := range chunk {
+					hashes = append(hashes, c.Hash)
+				}
+				got = append(got, hashes)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chunk() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if chunks := (Chunking{MaxCommits: 2}).chunk(nil); len(chunks) != 0 {
+		t.Errorf("chunk(nil) = %v, want no chunks", chunks)
+	}
+}
@@ -0,0 +1,559 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emilianohg/anchorman/internal/agent"
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/models"
+	"github.com/emilianohg/anchorman/internal/repository"
+)
+
+// Options selects which unprocessed commits are summarized
+type Options struct {
+	From      time.Time // zero = no lower bound
+	To        time.Time // zero = no upper bound
+	ProjectID *int64    // nil = all projects
+	CompanyID *int64    // nil = all companies
+}
+
+// Batch holds the commits of a single project and day that are sent to the agent together
+type Batch struct {
+	ProjectID   int64
+	ProjectName string
+	Day         time.Time // start of the day the commits were made, in the calendar's time zone
+	Commits     []models.RawCommit
+
+	CompanyName string // empty when the project has no company
+}
+
+// ProjectResult summarizes what was created for a single project
+type ProjectResult struct {
+	ProjectID     int64
+	ProjectName   string
+	Commits       int // commits summarized successfully
+	TasksCreated  int
+	FailedCommits []models.RawCommit // commits of batches that failed; they stay unprocessed
+	Err           error              // last batch error, nil when the whole project succeeded
+
+	Proposals []Proposal // unsaved tasks of each batch, in review mode
+}
+
+type Result struct {
+	Projects       []ProjectResult
+	TasksCreated   int
+	OrphanCommits  int // commits skipped because their repo has no project
+	CommitsSkipped int // commits skipped by the project/company filter
+
+	replace *Replacement // tasks deleted when the proposals are saved
+}
+
+// Failed returns the projects that had at least one failed batch
+func (r *Result) Failed() []ProjectResult {
+	var failed []ProjectResult
+	for _, p := range r.Projects {
+		if p.Err != nil {
+			failed = append(failed, p)
+		}
+	}
+	return failed
+}
+
+// FailedCommits returns the commits of all failed batches, for retrying them
+func (r *Result) FailedCommits() []models.RawCommit {
+	var commits []models.RawCommit
+	for _, p := range r.Projects {
+		commits = append(commits, p.FailedCommits...)
+	}
+	return commits
+}
+
+// Progress reports that a project started or finished processing
+type Progress struct {
+	Project  ProjectResult // totals so far; complete when Done is set
+	Done     bool
+	Finished int // projects finished so far, including this one when Done
+	Total    int // projects in the run
+}
+
+type Processor struct {
+	db          *sql.DB
+	agent       agent.Agent
+	cal         daterange.Calendar
+	progress    func(Progress)
+	concurrency int
+	chunking    Chunking
+	review      bool
+	prompts     *agent.PromptTemplates
+	replace     *Replacement
+
+	writeMu sync.Mutex // serializes database writes of concurrent projects
+}
+
+// New creates a processor that dates tasks using the given calendar
+func New(db *sql.DB, ag agent.Agent, cal daterange.Calendar) *Processor {
+	return &Processor{db: db, agent: ag, cal: cal, concurrency: 1}
+}
+
+// OnProgress registers fn to be called when a project starts and finishes.
+// When projects are processed in parallel, fn may be called from several workers
+// at once, so it must be safe for concurrent use.
+func (p *Processor) OnProgress(fn func(Progress)) *Processor {
+	p.progress = fn
+	return p
+}
+
+// WithConcurrency sets how many projects are sent to the agent at the same time
+func (p *Processor) WithConcurrency(n int) *Processor {
+	p.concurrency = max(n, 1)
+	return p
+}
+
+// WithChunking splits large batches into several agent calls
+func (p *Processor) WithChunking(c Chunking) *Processor {
+	p.chunking = c
+	return p
+}
+
+// WithReview keeps the tasks suggested by the agent in the result's proposals
+// instead of saving them, so they can be edited and then stored with Save
+func (p *Processor) WithReview(review bool) *Processor {
+	p.review = review
+	return p
+}
+
+// WithPrompts sends each project's configured prompt template to the agent
+func (p *Processor) WithPrompts(prompts *agent.PromptTemplates) *Processor {
+	p.prompts = prompts
+	return p
+}
+
+// WithReplacement deletes the tasks of r as the batches covering their commits are
+// saved, in the same transaction, so a failed or cancelled run keeps the old tasks
+func (p *Processor) WithReplacement(r *Replacement) *Processor {
+	p.replace = r
+	return p
+}
+
+// tracker counts finished projects and reports progress. Callbacks run after the
+// count is updated and outside of the lock, so a slow callback doesn't hold up
+// the other workers.
+type tracker struct {
+	mu       sync.Mutex
+	fn       func(Progress)
+	finished int
+	total    int
+}
+
+func (t *tracker) started(project ProjectResult) {
+	t.mu.Lock()
+	progress := Progress{Project: project, Finished: t.finished, Total: t.total}
+	t.mu.Unlock()
+
+	if t.fn != nil {
+		t.fn(progress)
+	}
+}
+
+func (t *tracker) done(project ProjectResult) {
+	t.mu.Lock()
+	t.finished++
+	progress := Progress{Project: project, Done: true, Finished: t.finished, Total: t.total}
+	t.mu.Unlock()
+
+	if t.fn != nil {
+		t.fn(progress)
+	}
+}
+
+// LoadCommits returns the unprocessed commits within the date range of opts
+func LoadCommits(db *sql.DB, opts Options) ([]models.RawCommit, error) {
+	commitRepo := repository.NewCommitRepo(db)
+
+	if opts.From.IsZero() && opts.To.IsZero() {
+		return commitRepo.GetUnprocessed()
+	}
+
+	to := opts.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	// Commit times are stored with the author's UTC offset, so the database
+	// comparison is padded by a day and the exact range is checked here
+	candidates, err := commitRepo.GetUnprocessedInDateRange(opts.From.Add(-24*time.Hour), to.Add(24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []models.RawCommit
+	for _, c := range candidates {
+		if c.CommittedAt.Before(opts.From) || c.CommittedAt.After(to) {
+			continue
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// minHashLength is the shortest abbreviated hash accepted from an agent
+const minHashLength = 7
+
+type batchKey struct {
+	projectID int64
+	day       time.Time
+}
+
+// Group splits commits into per-project, per-day batches, skipping orphan repos and
+// projects that don't match the project/company filter of opts. Days follow the
+// processor's calendar, so each task is dated on the day its work was committed.
+func (p *Processor) Group(commits []models.RawCommit, opts Options, result *Result) ([]Batch, error) {
+	repoRepo := repository.NewRepoRepo(p.db)
+	projectRepo := repository.NewProjectRepo(p.db)
+
+	repos := make(map[int64]*models.Repo)
+	projects := make(map[int64]*models.Project)
+	batches := make(map[batchKey]*Batch)
+
+	for _, c := range commits {
+		repo, ok := repos[c.RepoID]
+		if !ok {
+			var err error
+			repo, err = repoRepo.GetByID(c.RepoID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load repo %d: %w", c.RepoID, err)
+			}
+			repos[c.RepoID] = repo
+		}
+
+		if repo == nil || repo.ProjectID == nil {
+			// Skip orphan repos
+			result.OrphanCommits++
+			continue
+		}
+
+		projectID := *repo.ProjectID
+		project, ok := projects[projectID]
+		if !ok {
+			var err error
+			project, err = projectRepo.GetByID(projectID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load project %d: %w", projectID, err)
+			}
+			projects[projectID] = project
+		}
+
+		if !matches(project, opts) {
+			result.CommitsSkipped++
+			continue
+		}
+
+		key := batchKey{projectID: projectID, day: p.cal.Day(c.CommittedAt)}
+		batch, ok := batches[key]
+		if !ok {
+			name := repo.ProjectName
+			if name == "" {
+				name = "Unknown"
+			}
+			batch = &Batch{ProjectID: projectID, ProjectName: name, Day: key.day, CompanyName: project.CompanyName}
+			batches[key] = batch
+		}
+		batch.Commits = append(batch.Commits, c)
+	}
+
+	grouped := make([]Batch, 0, len(batches))
+	for _, b := range batches {
+		grouped = append(grouped, *b)
+	}
+	sort.Slice(grouped, func(i, j int) bool {
+		if grouped[i].ProjectName != grouped[j].ProjectName {
+			return grouped[i].ProjectName < grouped[j].ProjectName
+		}
+		if grouped[i].ProjectID != grouped[j].ProjectID {
+			return grouped[i].ProjectID < grouped[j].ProjectID
+		}
+		return grouped[i].Day.Before(grouped[j].Day)
+	})
+
+	return grouped, nil
+}
+
+func matches(project *models.Project, opts Options) bool {
+	if project == nil {
+		return false
+	}
+	if opts.ProjectID != nil && project.ID != *opts.ProjectID {
+		return false
+	}
+	if opts.CompanyID != nil && (project.CompanyID == nil || *project.CompanyID != *opts.CompanyID) {
+		return false
+	}
+	return true
+}
+
+// Process loads, groups and summarizes all unprocessed commits matching opts
+func (p *Processor) Process(ctx context.Context, opts Options) (*Result, error) {
+	commits, err := LoadCommits(p.db, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load commits: %w", err)
+	}
+
+	return p.ProcessCommits(ctx, commits, opts)
+}
+
+// ProcessCommits groups the given commits by project and day and summarizes each batch.
+// Projects are processed by up to the configured number of workers at a time,
+// while the batches of a single project run one after another.
+// A failing batch doesn't stop the run: its error and commits are recorded in the
+// project's result and the remaining batches are processed.
+// When ctx is cancelled it stops after the current batches and returns the partial
+// result along with ctx.Err(); batches that were already saved stay saved.
+func (p *Processor) ProcessCommits(ctx context.Context, commits []models.RawCommit, opts Options) (*Result, error) {
+	result := &Result{replace: p.replace}
+
+	batches, err := p.Group(commits, opts, result)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := byProject(batches)
+	results := make([]*ProjectResult, len(projects))
+	progress := &tracker{fn: p.progress, total: len(projects)}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(p.concurrency, len(projects)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = p.processProject(ctx, projects[i], progress)
+			}
+		}()
+	}
+
+	for i := range projects {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Keep the results in project order, whichever worker finished first
+	for _, pr := range results {
+		if pr != nil {
+			result.add(*pr)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// processProject summarizes the batches of a single project in order.
+// It returns nil when ctx was cancelled before the project started.
+func (p *Processor) processProject(ctx context.Context, batches []Batch, progress *tracker) *ProjectResult {
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	pr := &ProjectResult{
+		ProjectID:   batches[0].ProjectID,
+		ProjectName: batches[0].ProjectName,
+	}
+	progress.started(*pr)
+
+	for i, batch := range batches {
+		proposal, err := p.propose(ctx, batch)
+		if errors.Is(err, context.Canceled) {
+			// The interrupted batch and the ones after it stay unprocessed;
+			// record them so they can be retried
+			pr.Err = err
+			for _, rest := range batches[i:] {
+				pr.FailedCommits = append(pr.FailedCommits, rest.Commits...)
+			}
+			progress.done(*pr)
+			return pr
+		}
+
+		created := 0
+		if err == nil && !p.review {
+			created, err = p.save(proposal)
+		}
+		if err != nil {
+			pr.Err = err
+			pr.FailedCommits = append(pr.FailedCommits, batch.Commits...)
+			continue
+		}
+
+		if p.review {
+			pr.Proposals = append(pr.Proposals, proposal)
+		}
+		pr.TasksCreated += created
+		pr.Commits += len(batch.Commits)
+	}
+
+	progress.done(*pr)
+	return pr
+}
+
+// byProject splits batches, which are ordered by project, into one slice per project
+func byProject(batches []Batch) [][]Batch {
+	var projects [][]Batch
+	for i, b := range batches {
+		if i == 0 || b.ProjectID != batches[i-1].ProjectID {
+			projects = append(projects, nil)
+		}
+		projects[len(projects)-1] = append(projects[len(projects)-1], b)
+	}
+	return projects
+}
+
+func (r *Result) add(project ProjectResult) {
+	r.Projects = append(r.Projects, project)
+	r.TasksCreated += project.TasksCreated
+}
+
+// propose summarizes a batch and attributes each suggested task to its source commits
+func (p *Processor) propose(ctx context.Context, batch Batch) (Proposal, error) {
+	tasks, err := p.summarize(ctx, batch)
+	if errors.Is(err, context.Canceled) {
+		return Proposal{}, err
+	}
+	if err != nil {
+		return Proposal{}, fmt.Errorf("failed to process %s (%s): %w", batch.ProjectName, batch.Day.Format(daterange.DateLayout), err)
+	}
+
+	proposal := Proposal{Batch: batch}
+	for _, task := range tasks {
+		proposal.Tasks = append(proposal.Tasks, ProposedTask{
+			Description:    task.Description,
+			EstimatedHours: task.EstimatedHours,
+			Category:       task.Category,
+			Commits:        sourceCommits(batch.Commits, task.CommitHashes),
+		})
+	}
+	return proposal, nil
+}
+
+// save stores the tasks of a proposal, returning how many were created.
+// Tasks and processed flags are saved together, so an interrupted run never
+// leaves tasks for commits that will be processed again.
+func (p *Processor) save(proposal Proposal) (int, error) {
+	// Agent calls run in parallel, but SQLite allows a single writer
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+
+	created := 0
+	deleted := p.replace.replaced()
+	err := repository.WithTx(p.db, func(tx *sql.Tx) error {
+		var err error
+		created, err = saveProposal(p.db, tx, p.cal, proposal, p.replace, deleted)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if p.replace != nil {
+		p.replace.deleted = deleted
+	}
+	return created, nil
+}
+
+// summarize sends the batch to the agent, one chunk at a time when it exceeds the
+// chunking limits, and optionally merges the tasks of all chunks
+func (p *Processor) summarize(ctx context.Context, batch Batch) ([]agent.TaskResult, error) {
+	chunks := p.chunking.chunk(batch.Commits)
+
+	req := agent.Request{
+		Project:  batch.ProjectName,
+		Company:  batch.CompanyName,
+		Prompt:   p.prompts.For(batch.ProjectName, batch.CompanyName),
+		Language: p.prompts.Language(batch.CompanyName),
+	}
+
+	var tasks []agent.TaskResult
+	for i, chunk := range chunks {
+		req.Commits = chunk
+		chunkTasks, err := p.agent.Process(ctx, req)
+		if err != nil {
+			if len(chunks) > 1 && !errors.Is(err, context.Canceled) {
+				err = fmt.Errorf("chunk %d of %d: %w", i+1, len(chunks), err)
+			}
+			return nil, err
+		}
+		tasks = append(tasks, chunkTasks...)
+	}
+
+	if len(chunks) > 1 && p.chunking.Merge {
+		if merger, ok := p.agent.(agent.Merger); ok {
+			req.Commits = batch.Commits
+			merged, err := merger.Merge(ctx, req, tasks)
+			if err != nil {
+				return nil, fmt.Errorf("merge: %w", err)
+			}
+			tasks = merged
+		}
+	}
+
+	return tasks, nil
+}
+
+// sourceCommits returns the batch commits matching the hashes the agent attributed
+// to a task. Hashes may be abbreviated; unknown or ambiguous ones are ignored.
+// When none of them match, the task is attributed to the whole batch.
+func sourceCommits(commits []models.RawCommit, hashes []string) []models.RawCommit {
+	var source []models.RawCommit
+	seen := make(map[int64]bool)
+
+	for _, hash := range hashes {
+		hash = strings.ToLower(hash)
+		if len(hash) < minHashLength {
+			continue
+		}
+
+		var match *models.RawCommit
+		ambiguous := false
+		for i := range commits {
+			if strings.HasPrefix(strings.ToLower(commits[i].Hash), hash) {
+				if match != nil {
+					ambiguous = true
+					break
+				}
+				match = &commits[i]
+			}
+		}
+
+		if match != nil && !ambiguous && !seen[match.ID] {
+			seen[match.ID] = true
+			source = append(source, *match)
+		}
+	}
+
+	if len(source) == 0 {
+		return commits
+	}
+	return source
+}
+
+// taskDate returns the date of the most recent commit, in the reporting time zone
+func taskDate(cal daterange.Calendar, commits []models.RawCommit) time.Time {
+	var date time.Time
+	for _, c := range commits {
+		if c.CommittedAt.After(date) {
+			date = c.CommittedAt
+		}
+	}
+	return cal.In(date)
+}
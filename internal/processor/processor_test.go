@@ -0,0 +1,582 @@
+package processor
+
+import (
+	"context"
+	"crypto/sha1"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	_ "time/tzdata" // the calendar tests need America/New_York on every system
+
+	"github.com/emilianohg/anchorman/internal/agent"
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/db"
+	"github.com/emilianohg/anchorman/internal/models"
+	"github.com/emilianohg/anchorman/internal/repository"
+)
+
+// fixture is a database with two companies, their projects and repos, and one orphan repo
+type fixture struct {
+	db      *sql.DB
+	acme    int64 // company of the Web and API projects
+	other   int64 // company of the Secret project
+	web     int64
+	api     int64
+	secret  int64
+	repos   map[string]int64 // repo ID by project name, "" for the orphan repo
+	commits int
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	f := &fixture{db: database, repos: make(map[string]int64)}
+
+	companies := repository.NewCompanyRepo(database)
+	acme, err := companies.Create("Acme Corp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := companies.Create("Other Inc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.acme, f.other = acme.ID, other.ID
+
+	f.web = f.addProject(t, "Web", &f.acme)
+	f.api = f.addProject(t, "API", &f.acme)
+	f.secret = f.addProject(t, "Secret", &f.other)
+
+	orphan, err := repository.NewRepoRepo(database).Create("/src/orphan", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.repos[""] = orphan.ID
+
+	return f
+}
+
+// addProject creates a project with a single repo and returns the project ID
+func (f *fixture) addProject(t *testing.T, name string, companyID *int64) int64 {
+	t.Helper()
+	project, err := repository.NewProjectRepo(f.db).Create(name, companyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo, err := repository.NewRepoRepo(f.db).Create("/src/"+name, &project.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.repos[name] = repo.ID
+	return project.ID
+}
+
+// commit stores a commit in the repo of the named project ("" for the orphan repo)
+func (f *fixture) commit(t *testing.T, project, message string, at time.Time) models.RawCommit {
+	t.Helper()
+	f.commits++
+	hash := fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprint(f.commits))))
+	c, err := repository.NewCommitRepo(f.db).Create(f.repos[project], hash, message, "Ada Lovelace <ada@example.com>", "main", []string{"main.go"}, 0, 0, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *c
+}
+
+// agentFunc adapts a function to agent.Agent
+type agentFunc func(ctx context.Context, req agent.Request) ([]agent.TaskResult, error)
+
+func (f agentFunc) Process(ctx context.Context, req agent.Request) ([]agent.TaskResult, error) {
+	return f(ctx, req)
+}
+
+// tasks returns all stored tasks of the fixture
+func (f *fixture) tasks(t *testing.T) []models.Task {
+	t.Helper()
+	tasks, err := repository.NewTaskRepo(f.db).GetByDateRange(time.Time{}, time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tasks
+}
+
+func TestGroup(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := newFixture(t)
+	commits := []models.RawCommit{
+		f.commit(t, "Web", "web 1", time.Date(2025, 5, 13, 15, 0, 0, 0, time.UTC)),
+		// 02:00 UTC is still the evening of May 13 in New York
+		f.commit(t, "Web", "web 2", time.Date(2025, 5, 14, 2, 0, 0, 0, time.UTC)),
+		f.commit(t, "Web", "web 3", time.Date(2025, 5, 14, 16, 0, 0, 0, time.UTC)),
+		f.commit(t, "API", "api 1", time.Date(2025, 5, 14, 10, 0, 0, 0, time.UTC)),
+		f.commit(t, "Secret", "secret 1", time.Date(2025, 5, 13, 12, 0, 0, 0, time.UTC)),
+		f.commit(t, "", "orphan 1", time.Date(2025, 5, 13, 12, 0, 0, 0, time.UTC)),
+	}
+
+	type batch struct {
+		Project string
+		Day     string
+		Commits []string
+	}
+
+	tests := []struct {
+		name        string
+		cal         daterange.Calendar
+		opts        Options
+		want        []batch
+		wantOrphans int
+		wantSkipped int
+	}{
+		{
+			name: "utc",
+			cal:  daterange.Calendar{WeekStart: time.Monday, Location: time.UTC},
+			want: []batch{
+				{"API", "2025-05-14", []string{"api 1"}},
+				{"Secret", "2025-05-13", []string{"secret 1"}},
+				{"Web", "2025-05-13", []string{"web 1"}},
+				{"Web", "2025-05-14", []string{"web 2", "web 3"}},
+			},
+			wantOrphans: 1,
+		},
+		{
+			name: "calendar time zone",
+			cal:  daterange.Calendar{WeekStart: time.Monday, Location: newYork},
+			want: []batch{
+				{"API", "2025-05-14", []string{"api 1"}},
+				{"Secret", "2025-05-13", []string{"secret 1"}},
+				{"Web", "2025-05-13", []string{"web 1", "web 2"}},
+				{"Web", "2025-05-14", []string{"web 3"}},
+			},
+			wantOrphans: 1,
+		},
+		{
+			name: "company filter",
+			cal:  daterange.Calendar{WeekStart: time.Monday, Location: time.UTC},
+			opts: Options{CompanyID: &f.other},
+			want: []batch{
+				{"Secret", "2025-05-13", []string{"secret 1"}},
+			},
+			wantOrphans: 1,
+			wantSkipped: 4,
+		},
+		{
+			name: "project filter",
+			cal:  daterange.Calendar{WeekStart: time.Monday, Location: time.UTC},
+			opts: Options{ProjectID: &f.api},
+			want: []batch{
+				{"API", "2025-05-14", []string{"api 1"}},
+			},
+			wantOrphans: 1,
+			wantSkipped: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &Result{}
+			batches, err := New(f.db, nil, tt.cal).Group(commits, tt.opts, result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []batch
+			for _, b := range batches {
+				var messages []string
+				for _, c := range b.Commits {
+					messages = append(messages, c.Message)
+				}
+				if loc := b.Day.Location(); loc != tt.cal.Location {
+					t.Errorf("%s day is in %s, want %s", b.ProjectName, loc, tt.cal.Location)
+				}
+				got = append(got, batch{b.ProjectName, b.Day.Format(daterange.DateLayout), messages})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Group() = %+v, want %+v", got, tt.want)
+			}
+			if result.OrphanCommits != tt.wantOrphans || result.CommitsSkipped != tt.wantSkipped {
+				t.Errorf("orphans, skipped = %d, %d, want %d, %d",
+					result.OrphanCommits, result.CommitsSkipped, tt.wantOrphans, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestSourceCommits(t *testing.T) {
+	commits := []models.RawCommit{
+		{ID: 1, Hash: "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b"},
+		{ID: 2, Hash: "1a2b3c4ffff07a8b9c0d1e2f3a4b5c6d7e8f9a0b"},
+		{ID: 3, Hash: "9C0D1E2F3A4B5C6D7E8F9A0B1A2B3C4D5E6F7A8B"},
+	}
+
+	tests := []struct {
+		name   string
+		hashes []string
+		want   []int64
+	}{
+		{"full hash", []string{"1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b"}, []int64{1}},
+		{"abbreviated", []string{"1a2b3c4d"}, []int64{1}},
+		{"case insensitive", []string{"9c0d1e2"}, []int64{3}},
+		{"keeps the agent's order", []string{"9c0d1e2f", "1a2b3c4f"}, []int64{3, 2}},
+		{"duplicates", []string{"1a2b3c4d", "1a2b3c4d5e"}, []int64{1}},
+		{"ambiguous prefix is ignored", []string{"1a2b3c4", "9c0d1e2"}, []int64{3}},
+		{"too short is ignored", []string{"1a2b3c", "1a2b3c4f"}, []int64{2}},
+		{"unknown falls back to the batch", []string{"deadbeef"}, []int64{1, 2, 3}},
+		{"ambiguous only falls back to the batch", []string{"1a2b3c4"}, []int64{1, 2, 3}},
+		{"no hashes falls back to the batch", nil, []int64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int64
+			for _, c := range sourceCommits(commits, tt.hashes) {
+				got = append(got, c.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sourceCommits(%v) = %v, want %v", tt.hashes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessCommits(t *testing.T) {
+	f := newFixture(t)
+	commits := []models.RawCommit{
+		f.commit(t, "Web", "feat: add login", time.Date(2025, 5, 13, 9, 0, 0, 0, time.UTC)),
+		f.commit(t, "Web", "fix: login typo", time.Date(2025, 5, 13, 11, 0, 0, 0, time.UTC)),
+	}
+
+	ag := agentFunc(func(ctx context.Context, req agent.Request) ([]agent.TaskResult, error) {
+		return []agent.TaskResult{
+			{Description: "Added login", EstimatedHours: 1.5, CommitHashes: []string{req.Commits[0].Hash[:8]}, Category: "feature"},
+			{Description: "Fixed a typo", EstimatedHours: 0.5, CommitHashes: []string{req.Commits[1].Hash[:8]}},
+		}, nil
+	})
+
+	cal := daterange.Calendar{WeekStart: time.Monday, Location: time.UTC}
+	result, err := New(f.db, ag, cal).ProcessCommits(context.Background(), commits, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TasksCreated != 2 {
+		t.Errorf("TasksCreated = %d, want 2", result.TasksCreated)
+	}
+
+	type task struct {
+		Description string
+		Hours       float64
+		Category    string
+		Sources     []int64
+		Date        time.Time
+	}
+	var got []task
+	for _, tk := range f.tasks(t) {
+		got = append(got, task{tk.Description, tk.EstimatedHours, tk.Category, tk.SourceCommits, tk.TaskDate.UTC()})
+	}
+	want := []task{
+		{"Added login", 1.5, "feature", []int64{commits[0].ID}, commits[0].CommittedAt},
+		{"Fixed a typo", 0.5, models.CategoryOther, []int64{commits[1].ID}, commits[1].CommittedAt},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tasks = %+v, want %+v", got, want)
+	}
+
+	unprocessed, err := repository.NewCommitRepo(f.db).CountUnprocessed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unprocessed != 0 {
+		t.Errorf("%d commits left unprocessed, want 0", unprocessed)
+	}
+}
+
+func TestProcessCommitsCancelled(t *testing.T) {
+	f := newFixture(t)
+	day := func(d int) time.Time { return time.Date(2025, 5, d, 12, 0, 0, 0, time.UTC) }
+	commits := []models.RawCommit{
+		f.commit(t, "Web", "day 1", day(12)),
+		f.commit(t, "Web", "day 2", day(13)),
+		f.commit(t, "Web", "day 3", day(14)),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The second batch is interrupted while the agent is working on it
+	ag := agentFunc(func(ctx context.Context, req agent.Request) ([]agent.TaskResult, error) {
+		if req.Commits[0].Message == "day 2" {
+			cancel()
+			return nil, ctx.Err()
+		}
+		return []agent.TaskResult{{Description: req.Commits[0].Message, EstimatedHours: 1}}, nil
+	})
+
+	var done []ProjectResult
+	proc := New(f.db, ag, daterange.Calendar{WeekStart: time.Monday, Location: time.UTC}).OnProgress(func(p Progress) {
+		if p.Done {
+			done = append(done, p.Project)
+		}
+	})
+
+	result, err := proc.ProcessCommits(ctx, commits, Options{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ProcessCommits() error = %v, want context.Canceled", err)
+	}
+	if len(done) != 1 || done[0].ProjectName != "Web" {
+		t.Fatalf("finished projects = %+v, want Web", done)
+	}
+
+	var failed []string
+	for _, c := range result.FailedCommits() {
+		failed = append(failed, c.Message)
+	}
+	if want := []string{"day 2", "day 3"}; !reflect.DeepEqual(failed, want) {
+		t.Errorf("FailedCommits() = %v, want %v", failed, want)
+	}
+	if result.TasksCreated != 1 || result.Projects[0].Commits != 1 {
+		t.Errorf("TasksCreated, Commits = %d, %d, want 1, 1", result.TasksCreated, result.Projects[0].Commits)
+	}
+}
+
+func TestProcessCommitsWorkerPool(t *testing.T) {
+	// API fails, and earlier projects take longer so the workers finish out of order
+	delays := map[string]time.Duration{"API": 30 * time.Millisecond, "Secret": 15 * time.Millisecond}
+	heuristic := &agent.HeuristicAgent{}
+	ag := agentFunc(func(ctx context.Context, req agent.Request) ([]agent.TaskResult, error) {
+		time.Sleep(delays[req.Project])
+		if req.Project == "API" {
+			return nil, errors.New("agent unavailable")
+		}
+		return heuristic.Process(ctx, req)
+	})
+
+	type project struct {
+		Name    string
+		Commits int
+		Tasks   int
+		Failed  int
+		Err     bool
+	}
+	want := []project{
+		{Name: "API", Failed: 1, Err: true},
+		{Name: "Secret", Commits: 2, Tasks: 2},
+		{Name: "Web", Commits: 2, Tasks: 2},
+	}
+
+	for _, concurrency := range []int{1, 3} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			f := newFixture(t)
+			at := time.Date(2025, 5, 13, 12, 0, 0, 0, time.UTC)
+			commits := []models.RawCommit{
+				f.commit(t, "Web", "feat(ui): add dark mode", at),
+				f.commit(t, "Web", "fix(ui): fix contrast", at.Add(time.Hour)),
+				f.commit(t, "API", "feat: add rate limiting", at),
+				f.commit(t, "Secret", "chore: bump deps", at),
+				f.commit(t, "Secret", "chore: bump deps again", at.AddDate(0, 0, 1)),
+			}
+
+			var mu sync.Mutex
+			started, done := 0, 0
+			proc := New(f.db, ag, daterange.Calendar{WeekStart: time.Monday, Location: time.UTC}).
+				WithConcurrency(concurrency).
+				OnProgress(func(p Progress) {
+					mu.Lock()
+					defer mu.Unlock()
+					if p.Done {
+						done++
+					} else {
+						started++
+					}
+				})
+
+			result, err := proc.ProcessCommits(context.Background(), commits, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []project
+			for _, p := range result.Projects {
+				got = append(got, project{p.ProjectName, p.Commits, p.TasksCreated, len(p.FailedCommits), p.Err != nil})
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("projects = %+v, want %+v", got, want)
+			}
+			if result.TasksCreated != 4 || len(f.tasks(t)) != 4 {
+				t.Errorf("TasksCreated = %d with %d stored, want 4", result.TasksCreated, len(f.tasks(t)))
+			}
+			if started != 3 || done != 3 {
+				t.Errorf("progress started, done = %d, %d, want 3, 3", started, done)
+			}
+
+			// Only the failed project's commit is left for a retry
+			unprocessed, err := repository.NewCommitRepo(f.db).GetUnprocessed()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(unprocessed) != 1 || unprocessed[0].ID != commits[2].ID {
+				t.Errorf("unprocessed = %+v, want the API commit", unprocessed)
+			}
+		})
+	}
+}
+
+// mergingAgent returns one "Updated the UI" task per call and merges tasks like
+// the heuristic agent
+type mergingAgent struct {
+	chunks []int // number of commits of each Process call
+	merges int
+}
+
+func (a *mergingAgent) Process(ctx context.Context, req agent.Request) ([]agent.TaskResult, error) {
+	a.chunks = append(a.chunks, len(req.Commits))
+	var hashes []string
+	for _, c := range req.Commits {
+		hashes = append(hashes, c.Hash)
+	}
+	return []agent.TaskResult{{Description: "Updated the UI", EstimatedHours: 1, CommitHashes: hashes}}, nil
+}
+
+func (a *mergingAgent) Merge(ctx context.Context, req agent.Request, tasks []agent.TaskResult) ([]agent.TaskResult, error) {
+	a.merges++
+	return (&agent.HeuristicAgent{}).Merge(ctx, req, tasks)
+}
+
+func TestProcessCommitsChunked(t *testing.T) {
+	tests := []struct {
+		name       string
+		chunking   Chunking
+		wantChunks []int
+		wantMerges int
+		wantHours  []float64
+	}{
+		{"no limits", Chunking{Merge: true}, []int{5}, 0, []float64{1}},
+		{"chunks", Chunking{MaxCommits: 2}, []int{2, 2, 1}, 0, []float64{1, 1, 1}},
+		{"chunks merged", Chunking{MaxCommits: 2, Merge: true}, []int{2, 2, 1}, 1, []float64{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			at := time.Date(2025, 5, 13, 12, 0, 0, 0, time.UTC)
+			var commits []models.RawCommit
+			for i := range 5 {
+				commits = append(commits, f.commit(t, "Web", fmt.Sprintf("feat(ui): change %d", i), at.Add(time.Duration(i)*time.Hour)))
+			}
+
+			ag := &mergingAgent{}
+			proc := New(f.db, ag, daterange.Calendar{WeekStart: time.Monday, Location: time.UTC}).WithChunking(tt.chunking)
+			if _, err := proc.ProcessCommits(context.Background(), commits, Options{}); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(ag.chunks, tt.wantChunks) {
+				t.Errorf("chunks = %v, want %v", ag.chunks, tt.wantChunks)
+			}
+			if ag.merges != tt.wantMerges {
+				t.Errorf("merges = %d, want %d", ag.merges, tt.wantMerges)
+			}
+
+			var hours []float64
+			covered := 0
+			for _, task := range f.tasks(t) {
+				hours = append(hours, task.EstimatedHours)
+				covered += len(task.SourceCommits)
+			}
+			if !reflect.DeepEqual(hours, tt.wantHours) {
+				t.Errorf("task hours = %v, want %v", hours, tt.wantHours)
+			}
+			if covered != len(commits) {
+				t.Errorf("tasks cover %d commits, want %d", covered, len(commits))
+			}
+		})
+	}
+}
+
+func TestProcessCommitsChunkFailure(t *testing.T) {
+	f := newFixture(t)
+	at := time.Date(2025, 5, 13, 12, 0, 0, 0, time.UTC)
+	var commits []models.RawCommit
+	for i := range 3 {
+		commits = append(commits, f.commit(t, "Web", fmt.Sprintf("feat(ui): change %d", i), at))
+	}
+
+	calls := 0
+	ag := agentFunc(func(ctx context.Context, req agent.Request) ([]agent.TaskResult, error) {
+		if calls++; calls == 2 {
+			return nil, errors.New("agent unavailable")
+		}
+		return (&agent.HeuristicAgent{}).Process(ctx, req)
+	})
+
+	proc := New(f.db, ag, daterange.Calendar{WeekStart: time.Monday, Location: time.UTC}).WithChunking(Chunking{MaxCommits: 2})
+	result, err := proc.ProcessCommits(context.Background(), commits, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A failing chunk fails the whole batch, so no partial summary is saved
+	project := result.Projects[0]
+	if project.Err == nil || !strings.Contains(project.Err.Error(), "chunk 2 of 2") {
+		t.Errorf("Err = %v, want the failing chunk", project.Err)
+	}
+	if len(project.FailedCommits) != 3 {
+		t.Errorf("FailedCommits = %d, want 3", len(project.FailedCommits))
+	}
+	if tasks := f.tasks(t); len(tasks) != 0 {
+		t.Errorf("stored %d tasks, want none", len(tasks))
+	}
+}
+
+func TestProcessCommitsRollsBackFailedSave(t *testing.T) {
+	f := newFixture(t)
+	at := time.Date(2025, 5, 13, 12, 0, 0, 0, time.UTC)
+	commits := []models.RawCommit{
+		f.commit(t, "Web", "feat(ui): add dark mode", at),
+		f.commit(t, "Web", "fix(ui): fix contrast", at),
+	}
+
+	// Fail the write that marks the commits processed, after the tasks were inserted
+	if _, err := f.db.Exec(`
+		CREATE TRIGGER fail_mark_processed BEFORE UPDATE OF processed ON raw_commits
+		BEGIN SELECT RAISE(ABORT, 'disk full'); END
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	proc := New(f.db, &agent.HeuristicAgent{}, daterange.Calendar{WeekStart: time.Monday, Location: time.UTC})
+	result, err := proc.ProcessCommits(context.Background(), commits, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if project := result.Projects[0]; project.Err == nil || len(project.FailedCommits) != 2 {
+		t.Errorf("project = %+v, want an error and 2 failed commits", project)
+	}
+	if result.TasksCreated != 0 {
+		t.Errorf("TasksCreated = %d, want 0", result.TasksCreated)
+	}
+	if tasks := f.tasks(t); len(tasks) != 0 {
+		t.Errorf("stored %d tasks, want the inserts rolled back", len(tasks))
+	}
+	unprocessed, err := repository.NewCommitRepo(f.db).GetUnprocessed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unprocessed) != 2 {
+		t.Errorf("unprocessed = %d commits, want 2", len(unprocessed))
+	}
+}
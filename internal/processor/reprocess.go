@@ -0,0 +1,178 @@
+package processor
+
+import (
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/models"
+	"github.com/emilianohg/anchorman/internal/repository"
+)
+
+// Replacement lists the tasks a reprocessing run replaces and the commits they
+// were derived from, which are summarized again. Each task is deleted in the
+// transaction that saves the first batch covering one of its commits, so tasks
+// of batches that fail or are never saved are kept.
+type Replacement struct {
+	Tasks   []models.Task
+	Commits []models.RawCommit
+
+	deleted map[int64]bool // tasks already replaced by saved batches
+	related map[int64]bool // tasks outside of the range or filter that share a commit with one in it
+}
+
+// Related reports whether t is replaced only because it shares a source commit
+// with a task within the range and filter
+func (r *Replacement) Related(t models.Task) bool {
+	return r.related[t.ID]
+}
+
+// Hours returns the estimated hours of the tasks being replaced
+func (r *Replacement) Hours() float64 {
+	var hours float64
+	for _, t := range r.Tasks {
+		hours += t.EstimatedHours
+	}
+	return hours
+}
+
+// PlanReplacement finds the tasks dated within the range of opts that belong to
+// the projects matching its project/company filter, and every other task that
+// shares a source commit with one of them, so summarizing the commits again never
+// leaves a task behind that still covers one of them. Tasks added by hand have no
+// source commits and are never replaced.
+func PlanReplacement(db *sql.DB, cal daterange.Calendar, opts Options) (*Replacement, error) {
+	taskRepo := repository.NewTaskRepo(db)
+	commitRepo := repository.NewCommitRepo(db)
+
+	// Task dates are stored with their UTC offset, so the query is padded by a
+	// day and the exact range is checked below
+	from, to := opts.From, opts.To
+	if to.IsZero() {
+		to = time.Now().AddDate(100, 0, 0)
+	}
+	queryFrom, queryTo := from.Add(-24*time.Hour), to.Add(24*time.Hour)
+
+	var candidates []models.Task
+	var err error
+	switch {
+	case opts.ProjectID != nil:
+		candidates, err = taskRepo.GetByProjectAndDateRange(*opts.ProjectID, queryFrom, queryTo)
+	case opts.CompanyID != nil:
+		candidates, err = taskRepo.GetByCompanyAndDateRange(*opts.CompanyID, queryFrom, queryTo)
+	default:
+		candidates, err = taskRepo.GetByDateRange(queryFrom, queryTo)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tasks: %w", err)
+	}
+
+	replacement := &Replacement{related: make(map[int64]bool)}
+	var commitIDs, pending []int64
+	seen := make(map[int64]bool)
+	selected := make(map[int64]bool)
+
+	add := func(t models.Task) {
+		selected[t.ID] = true
+		t.TaskDate = cal.In(t.TaskDate)
+		replacement.Tasks = append(replacement.Tasks, t)
+
+		for _, id := range t.SourceCommits {
+			if !seen[id] {
+				seen[id] = true
+				commitIDs = append(commitIDs, id)
+				pending = append(pending, id)
+			}
+		}
+	}
+
+	for _, t := range candidates {
+		date := cal.In(t.TaskDate)
+		if date.Before(from) || date.After(to) || len(t.SourceCommits) == 0 {
+			continue
+		}
+		add(t)
+	}
+
+	// Tasks outside of the range or filter that share a commit with a selected
+	// task are replaced too, along with the rest of their commits
+	for len(pending) > 0 {
+		related, err := taskRepo.GetByCommitIDs(pending)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tasks: %w", err)
+		}
+		pending = nil
+		for _, t := range related {
+			if !selected[t.ID] {
+				replacement.related[t.ID] = true
+				add(t)
+			}
+		}
+	}
+
+	sort.SliceStable(replacement.Tasks, func(i, j int) bool {
+		a, b := replacement.Tasks[i], replacement.Tasks[j]
+		if a.ProjectName != b.ProjectName {
+			return a.ProjectName < b.ProjectName
+		}
+		return a.TaskDate.Before(b.TaskDate)
+	})
+
+	if replacement.Commits, err = commitRepo.GetByIDs(commitIDs); err != nil {
+		return nil, fmt.Errorf("failed to load commits: %w", err)
+	}
+
+	return replacement, nil
+}
+
+// replaced returns a copy of the tasks deleted so far, for a transaction to add to
+func (r *Replacement) replaced() map[int64]bool {
+	deleted := make(map[int64]bool)
+	if r != nil {
+		maps.Copy(deleted, r.deleted)
+	}
+	return deleted
+}
+
+// replaceTx deletes within tx the tasks derived from any of the given commits that
+// weren't deleted yet, adding them to deleted. Their commits outside of the given
+// ones are marked unprocessed, so they're summarized again if their own batch fails.
+func (r *Replacement) replaceTx(db *sql.DB, tx *sql.Tx, commits []models.RawCommit, deleted map[int64]bool) error {
+	if r == nil {
+		return nil
+	}
+
+	taskRepo := repository.NewTaskRepo(db)
+	commitRepo := repository.NewCommitRepo(db)
+
+	saved := make(map[int64]bool)
+	for _, c := range commits {
+		saved[c.ID] = true
+	}
+
+	var others []int64
+	for _, t := range r.Tasks {
+		if deleted[t.ID] || !slices.ContainsFunc(t.SourceCommits, func(id int64) bool { return saved[id] }) {
+			continue
+		}
+		if err := taskRepo.DeleteTx(tx, t.ID); err != nil {
+			return fmt.Errorf("failed to delete task %d: %w", t.ID, err)
+		}
+		deleted[t.ID] = true
+
+		for _, id := range t.SourceCommits {
+			if !saved[id] {
+				others = append(others, id)
+			}
+		}
+	}
+
+	if err := commitRepo.MarkUnprocessedTx(tx, others); err != nil {
+		return fmt.Errorf("failed to mark commits unprocessed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/emilianohg/anchorman/internal/agent"
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/models"
+	"github.com/emilianohg/anchorman/internal/repository"
+)
+
+// replacementFixture has processed Web commits on May 12, 13 and 20 and these tasks:
+//
+//	first    Web May 13  commits w1, w2
+//	second   Web May 20  commits w2, w3 (shares w2 with first)
+//	third    Web May 21  commit w3 (shares w3 with second)
+//	limits   API May 13  commit a1
+//	manual   Web May 13  no commits
+//	later    Web May 25  commit w4
+type replacementFixture struct {
+	*fixture
+	w1, w2, w3, w4, a1                          models.RawCommit
+	first, second, third, limits, manual, later int64
+}
+
+func newReplacementFixture(t *testing.T) *replacementFixture {
+	t.Helper()
+	f := &replacementFixture{fixture: newFixture(t)}
+	day := func(d int) time.Time { return time.Date(2025, 5, d, 12, 0, 0, 0, time.UTC) }
+
+	f.w1 = f.commit(t, "Web", "feat(ui): add dark mode", day(12))
+	f.w2 = f.commit(t, "Web", "fix(ui): fix contrast", day(13))
+	f.w3 = f.commit(t, "Web", "docs: document themes", day(20))
+	f.w4 = f.commit(t, "Web", "chore: bump deps", day(25))
+	f.a1 = f.commit(t, "API", "feat: add rate limiting", day(13))
+
+	commits := repository.NewCommitRepo(f.db)
+	if err := commits.MarkProcessed([]int64{f.w1.ID, f.w2.ID, f.w3.ID, f.w4.ID, f.a1.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := repository.NewTaskRepo(f.db)
+	create := func(project int64, description string, commits []int64, d int) int64 {
+		task, err := tasks.Create(project, description, commits, day(d), 1, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return task.ID
+	}
+	f.first = create(f.web, "Added dark mode", []int64{f.w1.ID, f.w2.ID}, 13)
+	f.second = create(f.web, "Fixed contrast", []int64{f.w2.ID, f.w3.ID}, 20)
+	f.third = create(f.web, "Documented themes", []int64{f.w3.ID}, 21)
+	f.limits = create(f.api, "Added rate limiting", []int64{f.a1.ID}, 13)
+	f.manual = create(f.web, "Planning meeting", []int64{}, 13)
+	f.later = create(f.web, "Bumped dependencies", []int64{f.w4.ID}, 25)
+
+	return f
+}
+
+func taskIDs(tasks []models.Task) []int64 {
+	var ids []int64
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestPlanReplacement(t *testing.T) {
+	f := newReplacementFixture(t)
+	cal := daterange.Calendar{WeekStart: time.Monday, Location: time.UTC}
+	from := time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 5, 14, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		opts        Options
+		wantTasks   []int64
+		wantRelated []int64
+		wantCommits []int64
+	}{
+		{
+			name:        "range",
+			opts:        Options{From: from, To: to},
+			wantTasks:   []int64{f.first, f.second, f.third, f.limits},
+			wantRelated: []int64{f.second, f.third},
+			wantCommits: []int64{f.w1.ID, f.w2.ID, f.w3.ID, f.a1.ID},
+		},
+		{
+			name:        "project",
+			opts:        Options{From: from, To: to, ProjectID: &f.api},
+			wantTasks:   []int64{f.limits},
+			wantCommits: []int64{f.a1.ID},
+		},
+		{
+			name:        "task sharing all of its commits",
+			opts:        Options{From: time.Date(2025, 5, 21, 0, 0, 0, 0, time.UTC), To: time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC)},
+			wantTasks:   []int64{f.first, f.second, f.third, f.later},
+			wantRelated: []int64{f.first, f.second},
+			wantCommits: []int64{f.w1.ID, f.w2.ID, f.w3.ID, f.w4.ID},
+		},
+		{
+			name: "nothing in range",
+			opts: Options{From: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := PlanReplacement(f.db, cal, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := taskIDs(r.Tasks); !reflect.DeepEqual(got, tt.wantTasks) {
+				t.Errorf("tasks = %v, want %v", got, tt.wantTasks)
+			}
+
+			var related []int64
+			for _, task := range r.Tasks {
+				if r.Related(task) {
+					related = append(related, task.ID)
+				}
+			}
+			slices.Sort(related)
+			if !reflect.DeepEqual(related, tt.wantRelated) {
+				t.Errorf("related tasks = %v, want %v", related, tt.wantRelated)
+			}
+
+			commits := commitIDs(r.Commits)
+			slices.Sort(commits)
+			if !reflect.DeepEqual(commits, tt.wantCommits) {
+				t.Errorf("commits = %v, want %v", commits, tt.wantCommits)
+			}
+
+			if want := float64(len(tt.wantTasks)); r.Hours() != want {
+				t.Errorf("Hours() = %v, want %v", r.Hours(), want)
+			}
+		})
+	}
+}
+
+func TestReprocess(t *testing.T) {
+	f := newReplacementFixture(t)
+	cal := daterange.Calendar{WeekStart: time.Monday, Location: time.UTC}
+
+	r, err := PlanReplacement(f.db, cal, Options{
+		From:      time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2025, 5, 14, 23, 59, 59, 0, time.UTC),
+		ProjectID: &f.web,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The batch of May 20 fails, so the tasks it would replace are kept
+	ag := agentFunc(func(ctx context.Context, req agent.Request) ([]agent.TaskResult, error) {
+		if req.Commits[0].ID == f.w3.ID {
+			return nil, errors.New("agent unavailable")
+		}
+		return (&agent.HeuristicAgent{}).Process(ctx, req)
+	})
+
+	result, err := New(f.db, ag, cal).WithReplacement(r).ProcessCommits(context.Background(), r.Commits, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TasksCreated != 2 {
+		t.Errorf("TasksCreated = %d, want 2", result.TasksCreated)
+	}
+
+	var kept, created []int64
+	for _, task := range f.tasks(t) {
+		if task.ID > f.later {
+			created = append(created, task.SourceCommits...)
+		} else {
+			kept = append(kept, task.ID)
+		}
+	}
+	slices.Sort(kept)
+	slices.Sort(created)
+
+	// first and second were replaced by the saved batches of May 12 and 13
+	if want := []int64{f.third, f.limits, f.manual, f.later}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("kept tasks = %v, want %v", kept, want)
+	}
+	if want := []int64{f.w1.ID, f.w2.ID}; !reflect.DeepEqual(created, want) {
+		t.Errorf("new tasks cover commits %v, want %v", created, want)
+	}
+
+	// w3 lost its task with second and its batch failed, so it's summarized again later
+	unprocessed, err := repository.NewCommitRepo(f.db).GetUnprocessed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := commitIDs(unprocessed); !reflect.DeepEqual(got, []int64{f.w3.ID}) {
+		t.Errorf("unprocessed = %v, want %v", got, []int64{f.w3.ID})
+	}
+}
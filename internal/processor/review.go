@@ -0,0 +1,142 @@
+package processor
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/models"
+	"github.com/emilianohg/anchorman/internal/repository"
+)
+
+// Proposal holds the tasks suggested for a batch before they are saved
+type Proposal struct {
+	Batch Batch
+	Tasks []ProposedTask // may be empty when every task was dropped; the commits are still marked processed
+}
+
+// ProposedTask is a task suggested by the agent that can be edited before it is saved
+type ProposedTask struct {
+	Description    string
+	EstimatedHours float64
+	Category       string
+	Commits        []models.RawCommit // source commits
+}
+
+// MergeTasks combines two tasks into one that covers the commits and hours of both.
+// The merged task keeps the category of a.
+func MergeTasks(a, b ProposedTask) ProposedTask {
+	merged := ProposedTask{
+		Description:    a.Description + "; " + b.Description,
+		EstimatedHours: a.EstimatedHours + b.EstimatedHours,
+		Category:       a.Category,
+		Commits:        append([]models.RawCommit{}, a.Commits...),
+	}
+
+	seen := make(map[int64]bool)
+	for _, c := range a.Commits {
+		seen[c.ID] = true
+	}
+	for _, c := range b.Commits {
+		if !seen[c.ID] {
+			seen[c.ID] = true
+			merged.Commits = append(merged.Commits, c)
+		}
+	}
+
+	return merged
+}
+
+// Split divides a task into two with half the hours each. The commits are divided
+// between them; a task with a single commit keeps it in both halves.
+func (t ProposedTask) Split() (ProposedTask, ProposedTask) {
+	first := ProposedTask{Description: t.Description, EstimatedHours: t.EstimatedHours / 2, Category: t.Category, Commits: t.Commits}
+	second := first
+
+	if n := len(t.Commits); n > 1 {
+		first.Commits = append([]models.RawCommit{}, t.Commits[:n/2]...)
+		second.Commits = append([]models.RawCommit{}, t.Commits[n/2:]...)
+	}
+
+	return first, second
+}
+
+// ProposedTasks returns how many tasks await review for the project
+func (pr ProjectResult) ProposedTasks() int {
+	n := 0
+	for _, proposal := range pr.Proposals {
+		n += len(proposal.Tasks)
+	}
+	return n
+}
+
+// Save stores the reviewed proposals of a result made in review mode and marks their
+// commits processed. When reprocessing, the tasks they replace are deleted as well.
+// Everything is saved in one transaction, so either all of the proposals are saved
+// or none of them are. The result's task counts are updated and its proposals cleared.
+func Save(db *sql.DB, cal daterange.Calendar, result *Result) error {
+	created := make([]int, len(result.Projects))
+	deleted := result.replace.replaced()
+
+	err := repository.WithTx(db, func(tx *sql.Tx) error {
+		for i, pr := range result.Projects {
+			for _, proposal := range pr.Proposals {
+				n, err := saveProposal(db, tx, cal, proposal, result.replace, deleted)
+				if err != nil {
+					return err
+				}
+				created[i] += n
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if result.replace != nil {
+		result.replace.deleted = deleted
+	}
+
+	result.TasksCreated = 0
+	for i := range result.Projects {
+		result.Projects[i].TasksCreated += created[i]
+		result.Projects[i].Proposals = nil
+		result.TasksCreated += result.Projects[i].TasksCreated
+	}
+	return nil
+}
+
+// saveProposal creates the tasks of a proposal, deletes the tasks of replace that
+// they supersede and marks all of its batch's commits processed within tx,
+// returning how many tasks were created
+func saveProposal(db *sql.DB, tx *sql.Tx, cal daterange.Calendar, proposal Proposal, replace *Replacement, deleted map[int64]bool) (int, error) {
+	taskRepo := repository.NewTaskRepo(db)
+	commitRepo := repository.NewCommitRepo(db)
+
+	if err := replace.replaceTx(db, tx, proposal.Batch.Commits, deleted); err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, task := range proposal.Tasks {
+		var sourceIDs []int64
+		for _, c := range task.Commits {
+			sourceIDs = append(sourceIDs, c.ID)
+		}
+
+		if _, err := taskRepo.CreateTx(tx, proposal.Batch.ProjectID, task.Description, sourceIDs, taskDate(cal, task.Commits), task.EstimatedHours, task.Category); err != nil {
+			return created, fmt.Errorf("failed to create task: %w", err)
+		}
+		created++
+	}
+
+	var commitIDs []int64
+	for _, c := range proposal.Batch.Commits {
+		commitIDs = append(commitIDs, c.ID)
+	}
+	if err := commitRepo.MarkProcessedTx(tx, commitIDs); err != nil {
+		return created, fmt.Errorf("failed to mark commits processed: %w", err)
+	}
+
+	return created, nil
+}
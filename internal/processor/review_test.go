@@ -0,0 +1,140 @@
+package processor
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emilianohg/anchorman/internal/agent"
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/models"
+	"github.com/emilianohg/anchorman/internal/repository"
+)
+
+func commitIDs(commits []models.RawCommit) []int64 {
+	var ids []int64
+	for _, c := range commits {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestMergeTasks(t *testing.T) {
+	c1, c2, c3 := models.RawCommit{ID: 1}, models.RawCommit{ID: 2}, models.RawCommit{ID: 3}
+	a := ProposedTask{Description: "Added dark mode", EstimatedHours: 1.5, Category: "feature", Commits: []models.RawCommit{c1, c2}}
+	b := ProposedTask{Description: "Fixed contrast", EstimatedHours: 0.5, Category: "fix", Commits: []models.RawCommit{c2, c3}}
+
+	merged := MergeTasks(a, b)
+
+	if merged.Description != "Added dark mode; Fixed contrast" {
+		t.Errorf("Description = %q, want %q", merged.Description, "Added dark mode; Fixed contrast")
+	}
+	if merged.EstimatedHours != 2 {
+		t.Errorf("EstimatedHours = %v, want 2", merged.EstimatedHours)
+	}
+	if merged.Category != "feature" {
+		t.Errorf("Category = %q, want %q", merged.Category, "feature")
+	}
+	if got, want := commitIDs(merged.Commits), []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Commits = %v, want %v", got, want)
+	}
+
+	// The merged task doesn't share its commits with a
+	merged.Commits[0].ID = 99
+	if a.Commits[0].ID != 1 {
+		t.Error("MergeTasks modified the commits of a")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	commits := []models.RawCommit{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	tests := []struct {
+		name       string
+		commits    []models.RawCommit
+		wantFirst  []int64
+		wantSecond []int64
+	}{
+		{"no commits", nil, nil, nil},
+		{"single commit", commits[:1], []int64{1}, []int64{1}},
+		{"two commits", commits[:2], []int64{1}, []int64{2}},
+		{"three commits", commits, []int64{1}, []int64{2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := ProposedTask{Description: "Updated the UI", EstimatedHours: 3, Category: "feature", Commits: tt.commits}
+			first, second := task.Split()
+
+			for _, half := range []ProposedTask{first, second} {
+				if half.Description != task.Description || half.Category != task.Category || half.EstimatedHours != 1.5 {
+					t.Errorf("half = %+v, want the description and category of the task and 1.5h", half)
+				}
+			}
+			if got := commitIDs(first.Commits); !reflect.DeepEqual(got, tt.wantFirst) {
+				t.Errorf("first commits = %v, want %v", got, tt.wantFirst)
+			}
+			if got := commitIDs(second.Commits); !reflect.DeepEqual(got, tt.wantSecond) {
+				t.Errorf("second commits = %v, want %v", got, tt.wantSecond)
+			}
+		})
+	}
+}
+
+func TestReviewAndSave(t *testing.T) {
+	f := newFixture(t)
+	at := time.Date(2025, 5, 13, 12, 0, 0, 0, time.UTC)
+	commits := []models.RawCommit{
+		f.commit(t, "Web", "feat(ui): add dark mode", at),
+		f.commit(t, "Web", "fix(ui): fix contrast", at),
+		f.commit(t, "API", "feat: add rate limiting", at),
+	}
+
+	cal := daterange.Calendar{WeekStart: time.Monday, Location: time.UTC}
+	result, err := New(f.db, &agent.HeuristicAgent{}, cal).WithReview(true).ProcessCommits(context.Background(), commits, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is stored until the proposals are saved
+	if tasks := f.tasks(t); len(tasks) != 0 {
+		t.Fatalf("stored %d tasks before Save, want none", len(tasks))
+	}
+	if result.TasksCreated != 0 {
+		t.Errorf("TasksCreated = %d before Save, want 0", result.TasksCreated)
+	}
+
+	api, web := &result.Projects[0], &result.Projects[1]
+	if api.ProposedTasks() != 1 || web.ProposedTasks() != 2 {
+		t.Fatalf("proposed tasks = %d (API), %d (Web), want 1, 2", api.ProposedTasks(), web.ProposedTasks())
+	}
+
+	// Merge the Web tasks and drop the API task; its commit is still marked processed
+	webTasks := web.Proposals[0].Tasks
+	web.Proposals[0].Tasks = []ProposedTask{MergeTasks(webTasks[0], webTasks[1])}
+	api.Proposals[0].Tasks = nil
+
+	if err := Save(f.db, cal, result); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks := f.tasks(t)
+	if len(tasks) != 1 {
+		t.Fatalf("stored %d tasks, want 1", len(tasks))
+	}
+	if tasks[0].ProjectID != f.web || len(tasks[0].SourceCommits) != 2 || tasks[0].Category != "feature" {
+		t.Errorf("task = %+v, want the merged Web feature task with 2 commits", tasks[0])
+	}
+	if result.TasksCreated != 1 || web.TasksCreated != 1 || web.Proposals != nil {
+		t.Errorf("result = %+v, want 1 task created and no proposals left", result)
+	}
+
+	unprocessed, err := repository.NewCommitRepo(f.db).GetUnprocessed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unprocessed) != 0 {
+		t.Errorf("unprocessed = %d commits, want 0", len(unprocessed))
+	}
+}
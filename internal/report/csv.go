@@ -0,0 +1,45 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVRenderer renders one row per task, intended for invoicing and spreadsheets.
+// Hours, authors and changed lines are always included regardless of the report toggles.
+type CSVRenderer struct{}
+
+func (c *CSVRenderer) Extension() string {
+	return ".csv"
+}
+
+func (c *CSVRenderer) Render(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write([]string{"company", "project", "date", "task", "hours", "authors", "insertions", "deletions"}); err != nil {
+		return err
+	}
+
+	for _, p := range r.Projects {
+		for _, t := range p.Tasks {
+			row := []string{
+				r.Company,
+				p.Name,
+				t.TaskDate.Format("2006-01-02"),
+				t.Description,
+				strconv.FormatFloat(t.EstimatedHours, 'f', 1, 64),
+				strings.Join(t.Authors, "; "),
+				strconv.Itoa(t.Insertions),
+				strconv.Itoa(t.Deletions),
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,88 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/locale"
+	"github.com/emilianohg/anchorman/internal/models"
+)
+
+// GroupBy selects how tasks are broken down inside a report
+type GroupBy string
+
+const (
+	GroupByProject     GroupBy = "project"      // flat task list per project
+	GroupByProjectDay  GroupBy = "project-day"  // per project, then per day
+	GroupByProjectWeek GroupBy = "project-week" // per project, then per week
+	GroupByDay         GroupBy = "day"          // per day across all projects
+)
+
+// Groupings lists the available breakdowns in the order they are offered in the TUI
+var Groupings = []GroupBy{GroupByProject, GroupByProjectDay, GroupByProjectWeek, GroupByDay}
+
+func ParseGroupBy(s string) (GroupBy, error) {
+	if s == "" {
+		return GroupByProject, nil
+	}
+	for _, g := range Groupings {
+		if string(g) == s {
+			return g, nil
+		}
+	}
+	return "", fmt.Errorf("unknown grouping: %s (expected project, project-day, project-week or day)", s)
+}
+
+// Group is a set of tasks that share a day or week
+type Group struct {
+	Label string // e.g. "Monday, Jan 13" or "Week 3 (Jan 13 - Jan 19)", in the report's locale
+	Start time.Time
+	End   time.Time
+	Hours float64
+	Tasks []models.Task
+}
+
+// groupTasks splits tasks into day or week groups ordered by date.
+// Tasks keep their relative order within a group.
+func groupTasks(tasks []models.Task, cal daterange.Calendar, loc *locale.Locale, weekly bool) []Group {
+	index := make(map[time.Time]int)
+	var groups []Group
+
+	for _, t := range tasks {
+		start, end := cal.Day(t.TaskDate), cal.EndOfDay(t.TaskDate)
+		if weekly {
+			start, end = cal.Week(t.TaskDate)
+		}
+
+		i, ok := index[start]
+		if !ok {
+			i = len(groups)
+			index[start] = i
+			groups = append(groups, Group{
+				Label: groupLabel(loc, start, end, weekly),
+				Start: start,
+				End:   end,
+			})
+		}
+		groups[i].Tasks = append(groups[i].Tasks, t)
+		groups[i].Hours += t.EstimatedHours
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Start.Before(groups[j].Start)
+	})
+
+	return groups
+}
+
+func groupLabel(loc *locale.Locale, start, end time.Time, weekly bool) string {
+	if !weekly {
+		return loc.Day(start)
+	}
+	// Number weeks by the ISO week of their fourth day, so Sunday-start
+	// weeks get the number of the Monday-start week they mostly overlap
+	_, week := start.AddDate(0, 0, 3).ISOWeek()
+	return fmt.Sprintf("%s %d (%s - %s)", loc.Week, week, loc.ShortDate(start), loc.ShortDate(end))
+}
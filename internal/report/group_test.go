@@ -0,0 +1,127 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/locale"
+	"github.com/emilianohg/anchorman/internal/models"
+)
+
+func TestParseGroupBy(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    GroupBy
+		wantErr bool
+	}{
+		{"", GroupByProject, false},
+		{"project", GroupByProject, false},
+		{"project-day", GroupByProjectDay, false},
+		{"project-week", GroupByProjectWeek, false},
+		{"day", GroupByDay, false},
+		{"week", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseGroupBy(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGroupBy(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseGroupBy(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupTasks(t *testing.T) {
+	task := func(description string, date time.Time, hours float64) models.Task {
+		return models.Task{Description: description, TaskDate: date, EstimatedHours: hours}
+	}
+	// Out of date order on purpose: groups are sorted, tasks keep their order
+	tasks := []models.Task{
+		task("b", day(2026, 1, 2), 1),
+		task("a", day(2025, 12, 29), 2),
+		task("c", day(2026, 1, 5), 0.5),
+		task("d", day(2026, 1, 2).Add(15*time.Hour), 1),
+		task("e", day(2025, 12, 28), 3),
+	}
+
+	type group struct {
+		Label string
+		Hours float64
+		Tasks []string
+	}
+
+	utc := daterange.Calendar{WeekStart: time.Monday, Location: time.UTC}
+
+	tests := []struct {
+		name   string
+		cal    daterange.Calendar
+		weekly bool
+		want   []group
+	}{
+		{
+			name: "daily",
+			cal:  utc,
+			want: []group{
+				{"Sunday, Dec 28", 3, []string{"e"}},
+				{"Monday, Dec 29", 2, []string{"a"}},
+				{"Friday, Jan 02", 2, []string{"b", "d"}},
+				{"Monday, Jan 05", 0.5, []string{"c"}},
+			},
+		},
+		{
+			// Dec 29, 2025 starts ISO week 1 of 2026
+			name:   "weekly across the year boundary",
+			cal:    utc,
+			weekly: true,
+			want: []group{
+				{"Week 52 (Dec 22 - Dec 28)", 3, []string{"e"}},
+				{"Week 1 (Dec 29 - Jan 04)", 4, []string{"b", "a", "d"}},
+				{"Week 2 (Jan 05 - Jan 11)", 0.5, []string{"c"}},
+			},
+		},
+		{
+			// Sunday-start weeks take the number of the ISO week they mostly overlap
+			name:   "weekly starting on Sunday",
+			cal:    daterange.Calendar{WeekStart: time.Sunday, Location: time.UTC},
+			weekly: true,
+			want: []group{
+				{"Week 1 (Dec 28 - Jan 03)", 7, []string{"b", "a", "d", "e"}},
+				{"Week 2 (Jan 04 - Jan 10)", 0.5, []string{"c"}},
+			},
+		},
+		{
+			// 15:00 UTC on Jan 2 is already Jan 3 in Tokyo
+			name: "daily in another time zone",
+			cal:  daterange.Calendar{WeekStart: time.Monday, Location: time.FixedZone("JST", 9*60*60)},
+			want: []group{
+				{"Sunday, Dec 28", 3, []string{"e"}},
+				{"Monday, Dec 29", 2, []string{"a"}},
+				{"Friday, Jan 02", 1, []string{"b"}},
+				{"Saturday, Jan 03", 1, []string{"d"}},
+				{"Monday, Jan 05", 0.5, []string{"c"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []group
+			for _, g := range groupTasks(tasks, tt.cal, locale.Default(), tt.weekly) {
+				var names []string
+				for _, task := range g.Tasks {
+					names = append(names, task.Description)
+				}
+				got = append(got, group{g.Label, g.Hours, names})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupTasks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
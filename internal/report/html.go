@@ -0,0 +1,98 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/emilianohg/anchorman/internal/models"
+)
+
+// HTMLRenderer renders a self-contained HTML document suitable for email bodies
+type HTMLRenderer struct{}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": strings.Join,
+	"task": func(r *Report, t models.Task) htmlTask { return htmlTask{Report: r, Task: t} },
+}).Parse(`<!DOCTYPE html>
+<html lang="{{.Locale.Code}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Company}} - {{.Locale.Report}}</title>
+</head>
+<body style="font-family: -apple-system, Helvetica, Arial, sans-serif; color: #222; max-width: 720px;">
+<h1>{{.Company}} - {{.Locale.Report}}</h1>
+<p><strong>{{.Locale.Period}}:</strong> {{.Locale.Date .From}} - {{.Locale.Date .To}}<br>
+<strong>{{.Locale.Generated}}:</strong> {{.GeneratedAt.Format "2006-01-02"}}</p>
+<hr>
+{{- if eq .GroupBy "day"}}
+{{- range .Days}}
+<h2>{{.Label}}</h2>
+<ul>
+{{- range .Tasks}}
+<li><strong>{{.ProjectName}}:</strong> {{template "task" (task $ .)}}</li>
+{{- end}}
+</ul>
+{{- if $.ShowTime}}
+<p><strong>{{$.Locale.DailyTotal}}: {{$.Locale.Hours .Hours}}</strong></p>
+{{- end}}
+{{- end}}
+{{- else}}
+{{- range .Projects}}
+<h2>{{.Name}}</h2>
+{{- if and $.ShowAuthors .Authors}}
+<p><strong>{{$.Locale.Contributors}}:</strong> {{join .Authors ", "}}</p>
+{{- end}}
+{{- if .Groups}}
+{{- range .Groups}}
+<h3>{{.Label}}</h3>
+<ul>
+{{- range .Tasks}}
+<li>{{template "task" (task $ .)}}</li>
+{{- end}}
+</ul>
+{{- if $.ShowTime}}
+<p><em>{{if eq $.GroupBy "project-week"}}{{$.Locale.WeeklyTotal}}{{else}}{{$.Locale.DailyTotal}}{{end}}: {{$.Locale.Hours .Hours}}</em></p>
+{{- end}}
+{{- end}}
+{{- else}}
+<ul>
+{{- range .Tasks}}
+<li>{{template "task" (task $ .)}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if $.ShowTime}}
+<p><strong>{{$.Locale.Subtotal}}: {{$.Locale.Hours .Hours}}</strong></p>
+{{- end}}
+{{- if $.ShowChanges}}
+<p><em>{{$.Locale.Changes}}: {{$.Locale.Churn .Insertions .Deletions}}</em></p>
+{{- end}}
+{{- end}}
+{{- end}}
+<hr>
+{{- if .ShowTime}}
+<p><strong>{{.Locale.Total}}: {{.Locale.Hours .TotalHours}}</strong></p>
+{{- end}}
+{{- if .ShowChanges}}
+<p><em>{{.Locale.Changes}}: {{.Locale.Churn .Insertions .Deletions}}</em></p>
+{{- end}}
+<p><em>{{.Locale.GeneratedBy}}</em></p>
+</body>
+</html>
+{{- define "task"}}{{.Task.Description}}{{if and .Report.ShowAuthors .Task.Authors}} ({{join .Task.Authors ", "}}){{end}}{{if .Report.ShowTime}} ({{.Report.Locale.Hours .Task.EstimatedHours}}){{end}}{{end}}
+`))
+
+// htmlTask pairs a task with its report so the shared "task" template can read the toggles
+type htmlTask struct {
+	Report *Report
+	Task   models.Task
+}
+
+func (h *HTMLRenderer) Extension() string {
+	return ".html"
+}
+
+func (h *HTMLRenderer) Render(w io.Writer, r *Report) error {
+	return htmlTemplate.Execute(w, r)
+}
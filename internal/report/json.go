@@ -0,0 +1,131 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/emilianohg/anchorman/internal/models"
+)
+
+// JSONRenderer renders the full report data for dashboards and other tools.
+// Hours, authors and changed lines are always included regardless of the report toggles.
+type JSONRenderer struct{}
+
+type jsonReport struct {
+	Company     string        `json:"company"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	GeneratedAt string        `json:"generated_at"`
+	TotalHours  float64       `json:"total_hours"`
+	TaskCount   int           `json:"task_count"`
+	Insertions  int           `json:"insertions"`
+	Deletions   int           `json:"deletions"`
+	GroupBy     string        `json:"group_by"`
+	Projects    []jsonProject `json:"projects"`
+	Days        []jsonGroup   `json:"days,omitempty"`
+}
+
+type jsonProject struct {
+	Name       string      `json:"name"`
+	Hours      float64     `json:"hours"`
+	Authors    []string    `json:"authors"`
+	Insertions int         `json:"insertions"`
+	Deletions  int         `json:"deletions"`
+	Tasks      []jsonTask  `json:"tasks"`
+	Groups     []jsonGroup `json:"groups,omitempty"`
+}
+
+type jsonGroup struct {
+	Label string     `json:"label"`
+	Start string     `json:"start"`
+	End   string     `json:"end"`
+	Hours float64    `json:"hours"`
+	Tasks []jsonTask `json:"tasks"`
+}
+
+type jsonTask struct {
+	Project     string   `json:"project"`
+	Description string   `json:"description"`
+	Date        string   `json:"date"`
+	Hours       float64  `json:"hours"`
+	Category    string   `json:"category"`
+	Authors     []string `json:"authors"`
+	Insertions  int      `json:"insertions"`
+	Deletions   int      `json:"deletions"`
+}
+
+func (j *JSONRenderer) Extension() string {
+	return ".json"
+}
+
+func (j *JSONRenderer) Render(w io.Writer, r *Report) error {
+	out := jsonReport{
+		Company:     r.Company,
+		From:        r.From.Format("2006-01-02"),
+		To:          r.To.Format("2006-01-02"),
+		GeneratedAt: r.GeneratedAt.Format("2006-01-02T15:04:05Z07:00"),
+		TotalHours:  r.TotalHours,
+		TaskCount:   r.TaskCount,
+		Insertions:  r.Insertions,
+		Deletions:   r.Deletions,
+		GroupBy:     string(r.GroupBy),
+		Projects:    []jsonProject{},
+		Days:        jsonGroups(r.Days),
+	}
+
+	for _, p := range r.Projects {
+		project := jsonProject{
+			Name:       p.Name,
+			Hours:      p.Hours,
+			Authors:    nonNil(p.Authors),
+			Insertions: p.Insertions,
+			Deletions:  p.Deletions,
+			Tasks:      jsonTasks(p.Tasks),
+			Groups:     jsonGroups(p.Groups),
+		}
+		out.Projects = append(out.Projects, project)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func jsonTasks(tasks []models.Task) []jsonTask {
+	out := []jsonTask{}
+	for _, t := range tasks {
+		out = append(out, jsonTask{
+			Project:     t.ProjectName,
+			Description: t.Description,
+			Date:        t.TaskDate.Format("2006-01-02"),
+			Hours:       t.EstimatedHours,
+			Category:    t.Category,
+			Authors:     nonNil(t.Authors),
+			Insertions:  t.Insertions,
+			Deletions:   t.Deletions,
+		})
+	}
+	return out
+}
+
+func jsonGroups(groups []Group) []jsonGroup {
+	var out []jsonGroup
+	for _, g := range groups {
+		out = append(out, jsonGroup{
+			Label: g.Label,
+			Start: g.Start.Format("2006-01-02"),
+			End:   g.End.Format("2006-01-02"),
+			Hours: g.Hours,
+			Tasks: jsonTasks(g.Tasks),
+		})
+	}
+	return out
+}
+
+// nonNil keeps empty lists as [] instead of null in the output
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type MarkdownRenderer struct{}
+
+func (m *MarkdownRenderer) Extension() string {
+	return ".md"
+}
+
+func (m *MarkdownRenderer) Render(w io.Writer, r *Report) error {
+	var md strings.Builder
+	l := r.Locale
+	md.WriteString(fmt.Sprintf("# %s - %s\n\n", r.Company, l.Report))
+	md.WriteString(fmt.Sprintf("**%s:** %s - %s\n", l.Period, l.Date(r.From), l.Date(r.To)))
+	md.WriteString(fmt.Sprintf("**%s:** %s\n\n", l.Generated, r.GeneratedAt.Format("2006-01-02")))
+	md.WriteString("---\n\n")
+
+	if r.GroupBy == GroupByDay {
+		for _, d := range r.Days {
+			md.WriteString(fmt.Sprintf("## %s\n\n", d.Label))
+			for _, t := range d.Tasks {
+				md.WriteString(fmt.Sprintf("- **%s:** %s\n", t.ProjectName, taskLine(r, t)))
+			}
+			if r.ShowTime {
+				md.WriteString(fmt.Sprintf("\n**%s: %s**\n", l.DailyTotal, l.Hours(d.Hours)))
+			}
+			md.WriteString("\n")
+		}
+	} else {
+		for _, p := range r.Projects {
+			md.WriteString(fmt.Sprintf("## %s\n\n", p.Name))
+
+			// Show project contributors if authors enabled
+			if r.ShowAuthors && len(p.Authors) > 0 {
+				md.WriteString(fmt.Sprintf("**%s:** %s\n\n", l.Contributors, strings.Join(p.Authors, ", ")))
+			}
+
+			if len(p.Groups) > 0 {
+				for _, g := range p.Groups {
+					md.WriteString(fmt.Sprintf("### %s\n\n", g.Label))
+					for _, t := range g.Tasks {
+						md.WriteString("- " + taskLine(r, t) + "\n")
+					}
+					if r.ShowTime {
+						md.WriteString(fmt.Sprintf("\n*%s: %s*\n", groupTotalLabel(r), l.Hours(g.Hours)))
+					}
+					md.WriteString("\n")
+				}
+			} else {
+				for _, t := range p.Tasks {
+					md.WriteString("- " + taskLine(r, t) + "\n")
+				}
+			}
+			if (r.ShowTime || r.ShowChanges) && len(p.Groups) == 0 {
+				md.WriteString("\n")
+			}
+			if r.ShowTime {
+				md.WriteString(fmt.Sprintf("**%s: %s**\n", l.Subtotal, l.Hours(p.Hours)))
+			}
+			if r.ShowChanges {
+				md.WriteString(fmt.Sprintf("*%s: %s*\n", l.Changes, l.Churn(p.Insertions, p.Deletions)))
+			}
+			md.WriteString("\n")
+		}
+	}
+
+	md.WriteString("---\n\n")
+	if r.ShowTime {
+		md.WriteString(fmt.Sprintf("**%s: %s**\n\n", l.Total, l.Hours(r.TotalHours)))
+	}
+	if r.ShowChanges {
+		md.WriteString(fmt.Sprintf("*%s: %s*\n\n", l.Changes, l.Churn(r.Insertions, r.Deletions)))
+	}
+	md.WriteString("*" + l.GeneratedBy + "*\n")
+
+	_, err := io.WriteString(w, md.String())
+	return err
+}
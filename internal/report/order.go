@@ -0,0 +1,75 @@
+package report
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/emilianohg/anchorman/internal/repository"
+)
+
+// Order selects how project sections are ordered in a report
+type Order string
+
+const (
+	OrderName   Order = "name"   // alphabetical by project name
+	OrderHours  Order = "hours"  // most hours first
+	OrderManual Order = "manual" // per-company order set in the Projects screen
+)
+
+// Orders lists the available project orders in the order they are offered in the TUI
+var Orders = []Order{OrderName, OrderHours, OrderManual}
+
+func ParseOrder(s string) (Order, error) {
+	if s == "" {
+		return OrderName, nil
+	}
+	for _, o := range Orders {
+		if string(o) == s {
+			return o, nil
+		}
+	}
+	return "", fmt.Errorf("unknown project order: %s (expected name, hours or manual)", s)
+}
+
+// sortProjects orders projects deterministically. Ties are always broken by name.
+func sortProjects(db *sql.DB, companyID int64, projects []Project, order Order) error {
+	byName := func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	}
+
+	switch order {
+	case OrderHours:
+		sort.SliceStable(projects, func(i, j int) bool {
+			if projects[i].Hours != projects[j].Hours {
+				return projects[i].Hours > projects[j].Hours
+			}
+			return byName(i, j)
+		})
+	case OrderManual:
+		companyProjects, err := repository.NewProjectRepo(db).GetByCompanyID(companyID)
+		if err != nil {
+			return err
+		}
+		// GetByCompanyID returns projects by sort_order, then name
+		rank := make(map[int64]int)
+		for i, p := range companyProjects {
+			rank[p.ID] = i
+		}
+		sort.SliceStable(projects, func(i, j int) bool {
+			ri, iok := rank[projects[i].ID]
+			rj, jok := rank[projects[j].ID]
+			if iok != jok {
+				return iok
+			}
+			if ri != rj {
+				return ri < rj
+			}
+			return byName(i, j)
+		})
+	default:
+		sort.SliceStable(projects, byName)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/emilianohg/anchorman/internal/repository"
+)
+
+func TestBuildOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		order Order
+		// setup runs against the fixture before the report is built
+		setup func(t *testing.T, f fixture)
+		want  []string
+	}{
+		{name: "default", want: []string{"API", "Web"}},
+		{name: "name", order: OrderName, want: []string{"API", "Web"}},
+		{name: "hours", order: OrderHours, want: []string{"Web", "API"}},
+		{
+			name:  "hours tie broken by name",
+			order: OrderHours,
+			setup: func(t *testing.T, f fixture) {
+				f.addTask(t, "API", "Code review", 1.5)
+			},
+			want: []string{"API", "Web"},
+		},
+		{
+			// Projects are created Web, API
+			name:  "manual follows creation order",
+			order: OrderManual,
+			want:  []string{"Web", "API"},
+		},
+		{
+			name:  "manual",
+			order: OrderManual,
+			setup: func(t *testing.T, f fixture) {
+				if err := repository.NewProjectRepo(f.db).SetSortOrder(f.projectIDs(t, "API", "Web")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: []string{"API", "Web"},
+		},
+		{
+			name:  "manual puts new projects last",
+			order: OrderManual,
+			setup: func(t *testing.T, f fixture) {
+				if err := repository.NewProjectRepo(f.db).SetSortOrder(f.projectIDs(t, "API", "Web")); err != nil {
+					t.Fatal(err)
+				}
+				f.addProject(t, "Admin")
+				f.addTask(t, "Admin", "Set up the backoffice", 1)
+			},
+			want: []string{"API", "Web", "Admin"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+
+			opts := f.options()
+			opts.Order = tt.order
+			var got []string
+			for _, p := range f.build(t, opts).Projects {
+				got = append(got, p.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("projects = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Order
+		wantErr bool
+	}{
+		{"", OrderName, false},
+		{"name", OrderName, false},
+		{"hours", OrderHours, false},
+		{"manual", OrderManual, false},
+		{"date", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseOrder(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseOrder(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseOrder(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Renderer turns a report into a document in a specific output format
+type Renderer interface {
+	Render(w io.Writer, r *Report) error
+	// Extension returns the file extension for generated files, including the dot
+	Extension() string
+}
+
+// Formats lists the built-in output formats in the order they are offered in the TUI
+var Formats = []string{"markdown", "html", "csv", "json", "text"}
+
+// NewRenderer returns the renderer for a built-in format, or for a user
+// template when format has the form "template:<name>"
+func NewRenderer(format string) (Renderer, error) {
+	if name, ok := strings.CutPrefix(format, templatePrefix); ok {
+		return LoadTemplate(name)
+	}
+
+	switch format {
+	case "", "markdown", "md":
+		return &MarkdownRenderer{}, nil
+	case "html":
+		return &HTMLRenderer{}, nil
+	case "csv":
+		return &CSVRenderer{}, nil
+	case "json":
+		return &JSONRenderer{}, nil
+	case "text", "txt":
+		return &TextRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown report format: %s", format)
+	}
+}
+
+// RenderString renders the report into a string
+func RenderString(r *Report, renderer Renderer) (string, error) {
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, r); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,278 @@
+package report
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/locale"
+	"github.com/emilianohg/anchorman/internal/models"
+	"github.com/emilianohg/anchorman/internal/repository"
+)
+
+// Options controls which tasks are included in a report and which details are shown
+type Options struct {
+	CompanyID   int64
+	From        time.Time
+	To          time.Time
+	ShowTime    bool // include time estimates and subtotals
+	ShowAuthors bool // include task authors and project contributors
+	ShowChanges bool // include lines added and removed per project
+	GroupBy     GroupBy
+	Order       Order
+	Calendar    daterange.Calendar // time zone and week start used to assign tasks to days and weeks
+
+	Locale *locale.Locale // labels, dates and numbers; nil = English
+}
+
+// Report holds everything needed to render a company report.
+// It is also the data passed to user templates, so its exported fields are part
+// of the template data model and should only be extended, not renamed.
+type Report struct {
+	Company     string
+	From        time.Time
+	To          time.Time
+	GeneratedAt time.Time
+	ShowTime    bool
+	ShowAuthors bool
+	ShowChanges bool
+	GroupBy     GroupBy
+	Order       Order
+	Projects    []Project
+	Days        []Group  // only set when grouping by day across projects
+	Authors     []string // unique authors across all projects
+	TotalHours  float64
+	TaskCount   int
+	Insertions  int // lines added by the commits of all tasks, each commit counted once
+	Deletions   int
+
+	Locale *locale.Locale // language of labels, dates and numbers
+}
+
+// Project is a report section with the tasks of a single project.
+// Each task exposes Description, TaskDate, EstimatedHours, Category, Authors,
+// Insertions, Deletions and ProjectName.
+type Project struct {
+	ID      int64
+	Name    string
+	Authors []string // unique authors across all tasks of the project
+	Hours   float64
+	Tasks   []models.Task
+	Groups  []Group // only set when grouping by day or week within projects
+
+	Insertions int // lines added by the commits of the project's tasks
+	Deletions  int
+}
+
+// Build loads the tasks of a company within the date range and groups them by project
+func Build(db *sql.DB, opts Options) (*Report, error) {
+	company, err := repository.NewCompanyRepo(db).GetByID(opts.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	if company == nil {
+		return nil, fmt.Errorf("company not found: %d", opts.CompanyID)
+	}
+
+	cal := opts.Calendar
+	if cal.Location == nil {
+		cal = daterange.DefaultCalendar()
+	}
+	loc := opts.Locale
+	if loc == nil {
+		loc = locale.Default()
+	}
+
+	// Older tasks are stored with the commit's UTC offset, so the database
+	// comparison is padded by a day and each task's day is checked here
+	taskRepo := repository.NewTaskRepo(db)
+	candidates, err := taskRepo.GetByCompanyAndDateRange(company.ID, opts.From.Add(-24*time.Hour), opts.To.Add(24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []models.Task
+	for _, t := range candidates {
+		t.TaskDate = cal.In(t.TaskDate)
+		if day := cal.Day(t.TaskDate); day.Before(cal.Day(opts.From)) || day.After(opts.To) {
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+
+	// Populate authors for each task
+	for i := range tasks {
+		authors, err := taskRepo.GetAuthorsForCommits(tasks[i].SourceCommits)
+		if err == nil {
+			tasks[i].Authors = authors
+		}
+	}
+
+	commits, err := sourceCommits(db, tasks)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i].Insertions, tasks[i].Deletions = changes(tasks[i:i+1], commits)
+	}
+
+	r := &Report{
+		Company:     company.Name,
+		From:        opts.From,
+		To:          opts.To,
+		GeneratedAt: time.Now(),
+		ShowTime:    opts.ShowTime,
+		ShowAuthors: opts.ShowAuthors,
+		ShowChanges: opts.ShowChanges,
+		GroupBy:     opts.GroupBy,
+		Order:       opts.Order,
+		TaskCount:   len(tasks),
+		Locale:      loc,
+	}
+	if r.GroupBy == "" {
+		r.GroupBy = GroupByProject
+	}
+	if r.Order == "" {
+		r.Order = OrderName
+	}
+
+	// Group tasks by project
+	index := make(map[int64]int)
+	for _, t := range tasks {
+		i, ok := index[t.ProjectID]
+		if !ok {
+			i = len(r.Projects)
+			index[t.ProjectID] = i
+			r.Projects = append(r.Projects, Project{ID: t.ProjectID, Name: t.ProjectName})
+		}
+		r.Projects[i].Tasks = append(r.Projects[i].Tasks, t)
+		r.Projects[i].Hours += t.EstimatedHours
+		r.TotalHours += t.EstimatedHours
+	}
+
+	if err := sortProjects(db, company.ID, r.Projects, r.Order); err != nil {
+		return nil, err
+	}
+
+	for i := range r.Projects {
+		r.Projects[i].Authors = projectAuthors(r.Projects[i].Tasks)
+		r.Projects[i].Insertions, r.Projects[i].Deletions = changes(r.Projects[i].Tasks, commits)
+
+		switch r.GroupBy {
+		case GroupByProjectDay:
+			r.Projects[i].Groups = groupTasks(r.Projects[i].Tasks, cal, loc, false)
+		case GroupByProjectWeek:
+			r.Projects[i].Groups = groupTasks(r.Projects[i].Tasks, cal, loc, true)
+		}
+	}
+	r.Authors = projectAuthors(tasks)
+	r.Insertions, r.Deletions = changes(tasks, commits)
+
+	if r.GroupBy == GroupByDay {
+		// Within a day, tasks follow the project order
+		var ordered []models.Task
+		for _, p := range r.Projects {
+			ordered = append(ordered, p.Tasks...)
+		}
+		r.Days = groupTasks(ordered, cal, loc, false)
+	}
+
+	return r, nil
+}
+
+// projectAuthors returns unique authors for the given tasks
+func projectAuthors(tasks []models.Task) []string {
+	seen := make(map[string]bool)
+	var authors []string
+	for _, t := range tasks {
+		for _, a := range t.Authors {
+			if !seen[a] {
+				seen[a] = true
+				authors = append(authors, a)
+			}
+		}
+	}
+	return authors
+}
+
+// sourceCommits loads the commits the tasks were derived from, by ID
+func sourceCommits(db *sql.DB, tasks []models.Task) (map[int64]models.RawCommit, error) {
+	var ids []int64
+	for _, t := range tasks {
+		ids = append(ids, t.SourceCommits...)
+	}
+
+	list, err := repository.NewCommitRepo(db).GetByIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load commits: %w", err)
+	}
+
+	commits := make(map[int64]models.RawCommit, len(list))
+	for _, c := range list {
+		commits[c.ID] = c
+	}
+	return commits, nil
+}
+
+// changes sums the lines added and removed by the commits of the given tasks,
+// counting a commit shared by several tasks once
+func changes(tasks []models.Task, commits map[int64]models.RawCommit) (int, int) {
+	seen := make(map[int64]bool)
+	insertions, deletions := 0, 0
+	for _, t := range tasks {
+		for _, id := range t.SourceCommits {
+			c, ok := commits[id]
+			if !ok || seen[id] {
+				continue
+			}
+			seen[id] = true
+			insertions += c.Insertions
+			deletions += c.Deletions
+		}
+	}
+	return insertions, deletions
+}
+
+// Filename returns the default file name for a report, e.g. acme_2025-01-01_to_2025-01-31.md
+func Filename(r *Report, renderer Renderer) string {
+	slug := strings.ToLower(strings.ReplaceAll(r.Company, " ", "-"))
+	return fmt.Sprintf("%s_%s_to_%s%s",
+		slug,
+		r.From.Format("2006-01-02"),
+		r.To.Format("2006-01-02"),
+		renderer.Extension(),
+	)
+}
+
+// Save renders the report into dir using the default file name and returns its path
+func Save(dir string, r *Report, renderer Renderer) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	path := filepath.Join(dir, Filename(r, renderer))
+	if err := WriteFile(path, r, renderer); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// WriteFile renders the report into the file at path
+func WriteFile(path string, r *Report, renderer Renderer) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	defer f.Close()
+
+	if err := renderer.Render(f, r); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,344 @@
+package report
+
+import (
+	"database/sql"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/db"
+	"github.com/emilianohg/anchorman/internal/locale"
+	"github.com/emilianohg/anchorman/internal/repository"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// fixture is a company with two projects whose tasks span the turn of the year,
+// plus tasks that must stay out of its reports
+type fixture struct {
+	db        *sql.DB
+	companyID int64
+	from, to  time.Time
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	companies := repository.NewCompanyRepo(database)
+	projects := repository.NewProjectRepo(database)
+	repos := repository.NewRepoRepo(database)
+	commits := repository.NewCommitRepo(database)
+	tasks := repository.NewTaskRepo(database)
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	acme, err := companies.Create("Acme Corp")
+	must(err)
+	other, err := companies.Create("Other Inc")
+	must(err)
+
+	// project creates a project with one repo and returns the project and repo IDs
+	project := func(name string, companyID int64, path string) (int64, int64) {
+		t.Helper()
+		p, err := projects.Create(name, &companyID)
+		must(err)
+		r, err := repos.Create(path, &p.ID)
+		must(err)
+		return p.ID, r.ID
+	}
+	// commit creates a commit with its lines added and removed and returns its ID
+	commit := func(repoID int64, hash, author string, at time.Time, insertions, deletions int) int64 {
+		t.Helper()
+		c, err := commits.Create(repoID, hash, "change "+hash, author, "main", []string{"main.go"}, insertions, deletions, at)
+		must(err)
+		return c.ID
+	}
+	task := func(projectID int64, description string, sources []int64, date time.Time, hours float64, category string) {
+		t.Helper()
+		_, err := tasks.Create(projectID, description, sources, date, hours, category)
+		must(err)
+	}
+
+	web, webRepo := project("Web", acme.ID, "/src/web")
+	api, apiRepo := project("API", acme.ID, "/src/api")
+	secret, secretRepo := project("Secret", other.ID, "/src/secret")
+
+	ada := "Ada Lovelace <ada@example.com>"
+	grace := "Grace Hopper <grace@example.com>"
+
+	w1 := commit(webRepo, "aaa111", ada, day(2025, 12, 29), 1200, 30)
+	w2 := commit(webRepo, "aaa222", grace, day(2025, 12, 31), 40, 5)
+	w3 := commit(webRepo, "aaa333", ada, day(2026, 1, 2), 12, 3)
+	a1 := commit(apiRepo, "bbb111", grace, day(2025, 12, 30), 250, 10)
+	s1 := commit(secretRepo, "ccc111", ada, day(2025, 12, 30), 5, 5)
+
+	task(web, "Redesigned the landing page", []int64{w1, w2}, day(2025, 12, 29), 3, "feature")
+	task(web, "Fixed the signup form", []int64{w3}, day(2026, 1, 2), 1.5, "fix")
+	task(api, "Added rate limiting", []int64{a1}, day(2025, 12, 30), 2, "feature")
+	task(api, "Planning meeting", nil, day(2026, 1, 5), 1, "")
+	task(api, "Before the period", []int64{a1}, day(2025, 12, 20), 4, "feature")
+	task(secret, "Secret work", []int64{s1}, day(2025, 12, 30), 8, "chore")
+
+	return fixture{db: database, companyID: acme.ID, from: day(2025, 12, 29), to: day(2026, 1, 5)}
+}
+
+// projectIDs returns the IDs of the fixture company's projects with the given names
+func (f fixture) projectIDs(t *testing.T, names ...string) []int64 {
+	t.Helper()
+	projects, err := repository.NewProjectRepo(f.db).GetByCompanyID(f.companyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int64
+	for _, name := range names {
+		for _, p := range projects {
+			if p.Name == name {
+				ids = append(ids, p.ID)
+			}
+		}
+	}
+	if len(ids) != len(names) {
+		t.Fatalf("projects %v not found", names)
+	}
+	return ids
+}
+
+// addProject adds a project to the fixture company
+func (f fixture) addProject(t *testing.T, name string) {
+	t.Helper()
+	if _, err := repository.NewProjectRepo(f.db).Create(name, &f.companyID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// addTask adds a manual task to a project of the fixture company on the first day of the period
+func (f fixture) addTask(t *testing.T, project, description string, hours float64) {
+	t.Helper()
+	id := f.projectIDs(t, project)[0]
+	if _, err := repository.NewTaskRepo(f.db).Create(id, description, nil, f.from, hours, ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// options returns the report options of the fixture's company and period
+func (f fixture) options() Options {
+	return Options{
+		CompanyID: f.companyID,
+		From:      f.from,
+		To:        f.to,
+		Calendar:  daterange.Calendar{WeekStart: time.Monday, Location: time.UTC},
+	}
+}
+
+// build builds a report of the fixture with a fixed generation time
+func (f fixture) build(t *testing.T, opts Options) *Report {
+	t.Helper()
+	r, err := Build(f.db, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.GeneratedAt = day(2026, 1, 6)
+	return r
+}
+
+// checkGolden compares got with testdata/name, rewriting the file with -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s does not match:\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	f := newFixture(t)
+	r := f.build(t, f.options())
+
+	if r.Company != "Acme Corp" {
+		t.Errorf("Company = %q, want Acme Corp", r.Company)
+	}
+	if r.TaskCount != 4 || r.TotalHours != 7.5 {
+		t.Errorf("TaskCount, TotalHours = %d, %v, want 4, 7.5", r.TaskCount, r.TotalHours)
+	}
+
+	// Commits shared by several tasks count once towards the project and report
+	if r.Insertions != 1502 || r.Deletions != 48 {
+		t.Errorf("Insertions, Deletions = %d, %d, want 1502, 48", r.Insertions, r.Deletions)
+	}
+
+	type summary struct {
+		Name       string
+		Hours      float64
+		Tasks      int
+		Authors    []string
+		Insertions int
+		Deletions  int
+	}
+	var got []summary
+	for _, p := range r.Projects {
+		got = append(got, summary{p.Name, p.Hours, len(p.Tasks), p.Authors, p.Insertions, p.Deletions})
+	}
+	want := []summary{
+		{"API", 3, 2, []string{"Grace H."}, 250, 10},
+		{"Web", 4.5, 2, []string{"Ada L.", "Grace H."}, 1252, 38},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("projects = %+v, want %+v", got, want)
+	}
+}
+
+func TestBuildUnknownCompany(t *testing.T) {
+	f := newFixture(t)
+	opts := f.options()
+	opts.CompanyID = 999
+
+	if _, err := Build(f.db, opts); err == nil {
+		t.Fatal("expected an error for an unknown company")
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	f := newFixture(t)
+
+	tests := []struct {
+		name        string
+		showTime    bool
+		showAuthors bool
+		showChanges bool
+		groupBy     GroupBy
+		locale      string
+	}{
+		{name: "plain"},
+		{name: "time_authors", showTime: true, showAuthors: true},
+		{name: "project-day", showTime: true, showAuthors: true, groupBy: GroupByProjectDay},
+		{name: "project-week", showTime: true, showAuthors: true, groupBy: GroupByProjectWeek},
+		{name: "day", showTime: true, showAuthors: true, groupBy: GroupByDay},
+		{name: "changes", showTime: true, showChanges: true},
+		{name: "es", showTime: true, showAuthors: true, groupBy: GroupByProjectWeek, locale: "es"},
+		{name: "es_changes", showTime: true, showChanges: true, locale: "es"},
+	}
+
+	for _, format := range Formats {
+		renderer, err := NewRenderer(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range tests {
+			t.Run(format+"/"+tt.name, func(t *testing.T) {
+				opts := f.options()
+				opts.ShowTime, opts.ShowAuthors, opts.ShowChanges = tt.showTime, tt.showAuthors, tt.showChanges
+				opts.GroupBy = tt.groupBy
+				if tt.locale != "" {
+					loc, err := locale.Parse(tt.locale)
+					if err != nil {
+						t.Fatal(err)
+					}
+					opts.Locale = loc
+				}
+
+				got, err := RenderString(f.build(t, opts), renderer)
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkGolden(t, format+"_"+tt.name+".golden", got)
+			})
+		}
+	}
+}
+
+func TestNewRendererUnknownFormat(t *testing.T) {
+	if _, err := NewRenderer("pdf"); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
+
+func TestFilename(t *testing.T) {
+	r := &Report{Company: "Acme Corp", From: day(2025, 12, 29), To: day(2026, 1, 5)}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"markdown", "acme-corp_2025-12-29_to_2026-01-05.md"},
+		{"html", "acme-corp_2025-12-29_to_2026-01-05.html"},
+		{"csv", "acme-corp_2025-12-29_to_2026-01-05.csv"},
+		{"json", "acme-corp_2025-12-29_to_2026-01-05.json"},
+		{"text", "acme-corp_2025-12-29_to_2026-01-05.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			renderer, err := NewRenderer(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Filename(r, renderer); got != tt.want {
+				t.Errorf("Filename() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	f := newFixture(t)
+
+	path := filepath.Join(t.TempDir(), "acme.html.tmpl")
+	content := `{{upper .Company}} by {{join .Authors ", "}}
+{{range .Projects}}{{.Name}} {{hours .Hours}}
+{{end}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	renderer, err := NewRenderer(TemplateFormat(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := renderer.Extension(); got != ".html" {
+		t.Errorf("Extension() = %q, want .html", got)
+	}
+
+	got, err := RenderString(f.build(t, f.options()), renderer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ACME CORP by Grace H., Ada L.\nAPI 3.0h\nWeb 4.5h\n"
+	if got != want {
+		t.Errorf("rendered %q, want %q", got, want)
+	}
+}
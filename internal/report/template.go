@@ -0,0 +1,109 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/emilianohg/anchorman/internal/config"
+)
+
+// templatePrefix marks a format string that refers to a user template, e.g. "template:acme"
+const templatePrefix = "template:"
+
+// TemplateRenderer renders a report with a user-defined text/template file.
+// The template receives the *Report as its data; see Report for the available fields.
+type TemplateRenderer struct {
+	Name      string
+	tmpl      *template.Template
+	extension string
+}
+
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"hours": func(h float64) string { return fmt.Sprintf("%.1fh", h) },
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// TemplateFormat returns the format string that selects the named template
+func TemplateFormat(name string) string {
+	return templatePrefix + name
+}
+
+// LoadTemplate loads a template by name from ~/.anchorman/templates, or by file path.
+//
+// A name like "acme" matches acme.tmpl or acme.<ext>.tmpl (e.g. acme.html.tmpl).
+// The extension before .tmpl becomes the extension of generated files; it defaults to .md.
+func LoadTemplate(name string) (*TemplateRenderer, error) {
+	path, err := findTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	base := filepath.Base(path)
+	tmpl, err := template.New(base).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", base, err)
+	}
+
+	ext := filepath.Ext(strings.TrimSuffix(base, ".tmpl"))
+	if ext == "" {
+		ext = ".md"
+	}
+
+	return &TemplateRenderer{Name: name, tmpl: tmpl, extension: ext}, nil
+}
+
+func findTemplate(name string) (string, error) {
+	// Explicit file path
+	if strings.ContainsRune(name, os.PathSeparator) {
+		if _, err := os.Stat(name); err != nil {
+			return "", fmt.Errorf("template not found: %s", name)
+		}
+		return name, nil
+	}
+
+	dir, err := config.TemplatesDir()
+	if err != nil {
+		return "", err
+	}
+
+	candidates := []string{
+		filepath.Join(dir, name),
+		filepath.Join(dir, name+".tmpl"),
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, name+".*.tmpl")); len(matches) > 0 {
+		candidates = append(candidates, matches[0])
+	}
+
+	for _, c := range candidates {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			return c, nil
+		}
+	}
+
+	return "", fmt.Errorf("template not found: %s (looked in %s)", name, dir)
+}
+
+func (t *TemplateRenderer) Extension() string {
+	return t.extension
+}
+
+func (t *TemplateRenderer) Render(w io.Writer, r *Report) error {
+	// Format hours in the report's locale
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return err
+	}
+	tmpl.Funcs(template.FuncMap{"hours": r.Locale.Hours})
+	return tmpl.Execute(w, r)
+}
@@ -0,0 +1,108 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/emilianohg/anchorman/internal/models"
+)
+
+// TextRenderer renders a plain-text report without any markup
+type TextRenderer struct{}
+
+func (t *TextRenderer) Extension() string {
+	return ".txt"
+}
+
+func (t *TextRenderer) Render(w io.Writer, r *Report) error {
+	var sb strings.Builder
+
+	l := r.Locale
+	title := fmt.Sprintf("%s - %s", r.Company, l.Report)
+	sb.WriteString(title + "\n")
+	sb.WriteString(strings.Repeat("=", utf8.RuneCountInString(title)) + "\n\n")
+
+	// Align the values of the header lines
+	width := max(utf8.RuneCountInString(l.Period), utf8.RuneCountInString(l.Generated)) + 2
+	sb.WriteString(fmt.Sprintf("%-*s%s - %s\n", width, l.Period+":", l.Date(r.From), l.Date(r.To)))
+	sb.WriteString(fmt.Sprintf("%-*s%s\n\n", width, l.Generated+":", r.GeneratedAt.Format("2006-01-02")))
+
+	if r.GroupBy == GroupByDay {
+		for _, d := range r.Days {
+			sb.WriteString(d.Label + "\n")
+			sb.WriteString(strings.Repeat("-", utf8.RuneCountInString(d.Label)) + "\n")
+			for _, task := range d.Tasks {
+				sb.WriteString(fmt.Sprintf("  * %s: %s\n", task.ProjectName, taskLine(r, task)))
+			}
+			if r.ShowTime {
+				sb.WriteString(fmt.Sprintf("  %s: %s\n", l.DailyTotal, l.Hours(d.Hours)))
+			}
+			sb.WriteString("\n")
+		}
+	} else {
+		for _, p := range r.Projects {
+			sb.WriteString(p.Name + "\n")
+			sb.WriteString(strings.Repeat("-", utf8.RuneCountInString(p.Name)) + "\n")
+
+			if r.ShowAuthors && len(p.Authors) > 0 {
+				sb.WriteString(fmt.Sprintf("%s: %s\n", l.Contributors, strings.Join(p.Authors, ", ")))
+			}
+
+			if len(p.Groups) > 0 {
+				for _, g := range p.Groups {
+					sb.WriteString("  " + g.Label + "\n")
+					for _, task := range g.Tasks {
+						sb.WriteString("    * " + taskLine(r, task) + "\n")
+					}
+					if r.ShowTime {
+						sb.WriteString(fmt.Sprintf("    %s: %s\n", groupTotalLabel(r), l.Hours(g.Hours)))
+					}
+				}
+			} else {
+				for _, task := range p.Tasks {
+					sb.WriteString("  * " + taskLine(r, task) + "\n")
+				}
+			}
+			if r.ShowTime {
+				sb.WriteString(fmt.Sprintf("  %s: %s\n", l.Subtotal, l.Hours(p.Hours)))
+			}
+			if r.ShowChanges {
+				sb.WriteString(fmt.Sprintf("  %s: %s\n", l.Changes, l.Churn(p.Insertions, p.Deletions)))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	if r.ShowTime {
+		sb.WriteString(fmt.Sprintf("%s: %s\n\n", l.Total, l.Hours(r.TotalHours)))
+	}
+	if r.ShowChanges {
+		sb.WriteString(fmt.Sprintf("%s: %s\n\n", l.Changes, l.Churn(r.Insertions, r.Deletions)))
+	}
+	sb.WriteString(l.GeneratedBy + "\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// groupTotalLabel names the subtotal of a day or week group
+func groupTotalLabel(r *Report) string {
+	if r.GroupBy == GroupByProjectWeek {
+		return r.Locale.WeeklyTotal
+	}
+	return r.Locale.DailyTotal
+}
+
+// taskLine formats a task description with optional authors and time estimate
+func taskLine(r *Report, t models.Task) string {
+	line := t.Description
+	if r.ShowAuthors && len(t.Authors) > 0 {
+		line += " (" + strings.Join(t.Authors, ", ") + ")"
+	}
+	if r.ShowTime {
+		line += " (" + r.Locale.Hours(t.EstimatedHours) + ")"
+	}
+	return line
+}
@@ -16,16 +16,25 @@ func NewCommitRepo(db *sql.DB) *CommitRepo {
 	return &CommitRepo{db: db}
 }
 
-func (r *CommitRepo) Create(repoID int64, hash, message, author, branch string, filesChanged []string, committedAt time.Time) (*models.RawCommit, error) {
+func (r *CommitRepo) Create(repoID int64, hash, message, author, branch string, filesChanged []string, insertions, deletions int, committedAt time.Time) (*models.RawCommit, error) {
+	return r.create(r.db, repoID, hash, message, author, branch, filesChanged, insertions, deletions, committedAt)
+}
+
+// CreateTx is Create within the transaction tx
+func (r *CommitRepo) CreateTx(tx *sql.Tx, repoID int64, hash, message, author, branch string, filesChanged []string, insertions, deletions int, committedAt time.Time) (*models.RawCommit, error) {
+	return r.create(tx, repoID, hash, message, author, branch, filesChanged, insertions, deletions, committedAt)
+}
+
+func (r *CommitRepo) create(q querier, repoID int64, hash, message, author, branch string, filesChanged []string, insertions, deletions int, committedAt time.Time) (*models.RawCommit, error) {
 	filesJSON, err := json.Marshal(filesChanged)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := r.db.Exec(`
-		INSERT INTO raw_commits (repo_id, hash, message, author, branch, files_changed, committed_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, repoID, hash, message, author, branch, string(filesJSON), committedAt)
+	result, err := q.Exec(`
+		INSERT INTO raw_commits (repo_id, hash, message, author, branch, files_changed, insertions, deletions, committed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, repoID, hash, message, author, branch, string(filesJSON), insertions, deletions, committedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -35,22 +44,26 @@ func (r *CommitRepo) Create(repoID int64, hash, message, author, branch string,
 		return nil, err
 	}
 
-	return r.GetByID(id)
+	return r.getByID(q, id)
 }
 
 func (r *CommitRepo) GetByID(id int64) (*models.RawCommit, error) {
+	return r.getByID(r.db, id)
+}
+
+func (r *CommitRepo) getByID(q querier, id int64) (*models.RawCommit, error) {
 	var c models.RawCommit
 	var filesJSON string
 
-	err := r.db.QueryRow(`
+	err := q.QueryRow(`
 		SELECT rc.id, rc.repo_id, rc.hash, rc.message, rc.author, rc.branch,
-		       rc.files_changed, rc.committed_at, rc.processed, rc.created_at, r.path
+		       rc.files_changed, rc.insertions, rc.deletions, rc.committed_at, rc.processed, rc.created_at, r.path
 		FROM raw_commits rc
 		JOIN repos r ON r.id = rc.repo_id
 		WHERE rc.id = ?
 	`, id).Scan(
 		&c.ID, &c.RepoID, &c.Hash, &c.Message, &c.Author, &c.Branch,
-		&filesJSON, &c.CommittedAt, &c.Processed, &c.CreatedAt, &c.RepoPath,
+		&filesJSON, &c.Insertions, &c.Deletions, &c.CommittedAt, &c.Processed, &c.CreatedAt, &c.RepoPath,
 	)
 
 	if err == sql.ErrNoRows {
@@ -73,13 +86,13 @@ func (r *CommitRepo) GetByRepoAndHash(repoID int64, hash string) (*models.RawCom
 
 	err := r.db.QueryRow(`
 		SELECT rc.id, rc.repo_id, rc.hash, rc.message, rc.author, rc.branch,
-		       rc.files_changed, rc.committed_at, rc.processed, rc.created_at, r.path
+		       rc.files_changed, rc.insertions, rc.deletions, rc.committed_at, rc.processed, rc.created_at, r.path
 		FROM raw_commits rc
 		JOIN repos r ON r.id = rc.repo_id
 		WHERE rc.repo_id = ? AND rc.hash = ?
 	`, repoID, hash).Scan(
 		&c.ID, &c.RepoID, &c.Hash, &c.Message, &c.Author, &c.Branch,
-		&filesJSON, &c.CommittedAt, &c.Processed, &c.CreatedAt, &c.RepoPath,
+		&filesJSON, &c.Insertions, &c.Deletions, &c.CommittedAt, &c.Processed, &c.CreatedAt, &c.RepoPath,
 	)
 
 	if err == sql.ErrNoRows {
@@ -117,7 +130,7 @@ func (r *CommitRepo) GetUnprocessedByProjectID(projectID int64) ([]models.RawCom
 func (r *CommitRepo) getCommitsWithFilter(filter string, args []interface{}) ([]models.RawCommit, error) {
 	query := `
 		SELECT rc.id, rc.repo_id, rc.hash, rc.message, rc.author, rc.branch,
-		       rc.files_changed, rc.committed_at, rc.processed, rc.created_at, re.path
+		       rc.files_changed, rc.insertions, rc.deletions, rc.committed_at, rc.processed, rc.created_at, re.path
 		FROM raw_commits rc
 		JOIN repos re ON re.id = rc.repo_id
 		` + filter + `
@@ -137,7 +150,7 @@ func (r *CommitRepo) getCommitsWithFilter(filter string, args []interface{}) ([]
 
 		if err := rows.Scan(
 			&c.ID, &c.RepoID, &c.Hash, &c.Message, &c.Author, &c.Branch,
-			&filesJSON, &c.CommittedAt, &c.Processed, &c.CreatedAt, &c.RepoPath,
+			&filesJSON, &c.Insertions, &c.Deletions, &c.CommittedAt, &c.Processed, &c.CreatedAt, &c.RepoPath,
 		); err != nil {
 			return nil, err
 		}
@@ -152,28 +165,62 @@ func (r *CommitRepo) getCommitsWithFilter(filter string, args []interface{}) ([]
 }
 
 func (r *CommitRepo) MarkProcessed(ids []int64) error {
+	return r.setProcessed(r.db, ids, true)
+}
+
+// MarkProcessedTx is MarkProcessed within the transaction tx
+func (r *CommitRepo) MarkProcessedTx(tx *sql.Tx, ids []int64) error {
+	return r.setProcessed(tx, ids, true)
+}
+
+// MarkUnprocessedTx marks commits unprocessed within the transaction tx, so
+// they are summarized again by the next processing run
+func (r *CommitRepo) MarkUnprocessedTx(tx *sql.Tx, ids []int64) error {
+	return r.setProcessed(tx, ids, false)
+}
+
+func (r *CommitRepo) setProcessed(q querier, ids []int64, processed bool) error {
 	if len(ids) == 0 {
 		return nil
 	}
 
 	// Build placeholders
 	placeholders := ""
-	args := make([]interface{}, len(ids))
+	args := []interface{}{processed}
 	for i, id := range ids {
 		if i > 0 {
 			placeholders += ","
 		}
 		placeholders += "?"
-		args[i] = id
+		args = append(args, id)
 	}
 
-	_, err := r.db.Exec(
-		"UPDATE raw_commits SET processed = 1 WHERE id IN ("+placeholders+")",
+	_, err := q.Exec(
+		"UPDATE raw_commits SET processed = ? WHERE id IN ("+placeholders+")",
 		args...,
 	)
 	return err
 }
 
+// GetByIDs returns the commits with the given IDs, oldest first
+func (r *CommitRepo) GetByIDs(ids []int64) ([]models.RawCommit, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders := ""
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		if i > 0 {
+			placeholders += ","
+		}
+		placeholders += "?"
+		args[i] = id
+	}
+
+	return r.getCommitsWithFilter("WHERE rc.id IN ("+placeholders+")", args)
+}
+
 func (r *CommitRepo) CountUnprocessed() (int, error) {
 	var count int
 	err := r.db.QueryRow("SELECT COUNT(*) FROM raw_commits WHERE processed = 0").Scan(&count)
@@ -208,16 +255,26 @@ func (r *CommitRepo) GetLastProcessedTime() (*time.Time, error) {
 }
 
 // UpdateAndMarkUnprocessed updates commit data and marks it as unprocessed
-func (r *CommitRepo) UpdateAndMarkUnprocessed(id int64, message, author, branch string, filesChanged []string, committedAt time.Time) error {
+func (r *CommitRepo) UpdateAndMarkUnprocessed(id int64, message, author, branch string, filesChanged []string, insertions, deletions int, committedAt time.Time) error {
+	return r.updateAndMarkUnprocessed(r.db, id, message, author, branch, filesChanged, insertions, deletions, committedAt)
+}
+
+// UpdateAndMarkUnprocessedTx is UpdateAndMarkUnprocessed within the transaction tx
+func (r *CommitRepo) UpdateAndMarkUnprocessedTx(tx *sql.Tx, id int64, message, author, branch string, filesChanged []string, insertions, deletions int, committedAt time.Time) error {
+	return r.updateAndMarkUnprocessed(tx, id, message, author, branch, filesChanged, insertions, deletions, committedAt)
+}
+
+func (r *CommitRepo) updateAndMarkUnprocessed(q querier, id int64, message, author, branch string, filesChanged []string, insertions, deletions int, committedAt time.Time) error {
 	filesJSON, err := json.Marshal(filesChanged)
 	if err != nil {
 		return err
 	}
 
-	_, err = r.db.Exec(`
+	_, err = q.Exec(`
 		UPDATE raw_commits
-		SET message = ?, author = ?, branch = ?, files_changed = ?, committed_at = ?, processed = 0
+		SET message = ?, author = ?, branch = ?, files_changed = ?, insertions = ?, deletions = ?,
+		    committed_at = ?, processed = 0
 		WHERE id = ?
-	`, message, author, branch, string(filesJSON), committedAt, id)
+	`, message, author, branch, string(filesJSON), insertions, deletions, committedAt, id)
 	return err
 }
@@ -44,6 +44,23 @@ func (r *CompanyRepo) GetByID(id int64) (*models.Company, error) {
 	return &c, nil
 }
 
+// GetByName finds a company by name, ignoring case
+func (r *CompanyRepo) GetByName(name string) (*models.Company, error) {
+	var c models.Company
+	err := r.db.QueryRow(
+		"SELECT id, name, created_at FROM companies WHERE name = ? COLLATE NOCASE",
+		name,
+	).Scan(&c.ID, &c.Name, &c.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
 func (r *CompanyRepo) GetAll() ([]models.Company, error) {
 	rows, err := r.db.Query("SELECT id, name, created_at FROM companies ORDER BY name")
 	if err != nil {
@@ -77,6 +94,8 @@ type CompanyWithStats struct {
 	ProjectCount int
 	RepoCount    int
 	TaskCount    int
+	Insertions   int // lines added by the commits of the company's repos
+	Deletions    int
 }
 
 func (r *CompanyRepo) GetAllWithStats() ([]CompanyWithStats, error) {
@@ -85,7 +104,13 @@ func (r *CompanyRepo) GetAllWithStats() ([]CompanyWithStats, error) {
 			c.id, c.name, c.created_at,
 			COUNT(DISTINCT p.id) as project_count,
 			COUNT(DISTINCT r.id) as repo_count,
-			COUNT(DISTINCT t.id) as task_count
+			COUNT(DISTINCT t.id) as task_count,
+			(SELECT COALESCE(SUM(rc.insertions), 0) FROM raw_commits rc
+			 JOIN repos cr ON cr.id = rc.repo_id JOIN projects cp ON cp.id = cr.project_id
+			 WHERE cp.company_id = c.id) as insertions,
+			(SELECT COALESCE(SUM(rc.deletions), 0) FROM raw_commits rc
+			 JOIN repos cr ON cr.id = rc.repo_id JOIN projects cp ON cp.id = cr.project_id
+			 WHERE cp.company_id = c.id) as deletions
 		FROM companies c
 		LEFT JOIN projects p ON p.company_id = c.id
 		LEFT JOIN repos r ON r.project_id = p.id
@@ -104,7 +129,7 @@ func (r *CompanyRepo) GetAllWithStats() ([]CompanyWithStats, error) {
 		var c CompanyWithStats
 		if err := rows.Scan(
 			&c.ID, &c.Name, &c.CreatedAt,
-			&c.ProjectCount, &c.RepoCount, &c.TaskCount,
+			&c.ProjectCount, &c.RepoCount, &c.TaskCount, &c.Insertions, &c.Deletions,
 		); err != nil {
 			return nil, err
 		}
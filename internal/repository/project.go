@@ -14,10 +14,11 @@ func NewProjectRepo(db *sql.DB) *ProjectRepo {
 	return &ProjectRepo{db: db}
 }
 
+// Create adds a project after the existing projects of its company
 func (r *ProjectRepo) Create(name string, companyID *int64) (*models.Project, error) {
 	result, err := r.db.Exec(
-		"INSERT INTO projects (name, company_id) VALUES (?, ?)",
-		name, companyID,
+		"INSERT INTO projects (name, company_id, sort_order) VALUES (?, ?, "+nextSortOrder+")",
+		name, companyID, companyID,
 	)
 	if err != nil {
 		return nil, err
@@ -96,7 +97,7 @@ func (r *ProjectRepo) GetByCompanyID(companyID int64) ([]models.Project, error)
 		FROM projects p
 		LEFT JOIN companies c ON c.id = p.company_id
 		WHERE p.company_id = ?
-		ORDER BY p.name
+		ORDER BY p.sort_order, p.name
 	`, companyID)
 	if err != nil {
 		return nil, err
@@ -123,6 +124,41 @@ func (r *ProjectRepo) GetByCompanyID(companyID int64) ([]models.Project, error)
 	return projects, rows.Err()
 }
 
+// GetByName returns all projects with the given name, ignoring case.
+// Project names are only unique within a company, so several may match.
+func (r *ProjectRepo) GetByName(name string) ([]models.Project, error) {
+	rows, err := r.db.Query(`
+		SELECT p.id, p.name, p.company_id, p.created_at, c.name
+		FROM projects p
+		LEFT JOIN companies c ON c.id = p.company_id
+		WHERE p.name = ? COLLATE NOCASE
+		ORDER BY c.name, p.name
+	`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []models.Project
+	for rows.Next() {
+		var p models.Project
+		var companyID sql.NullInt64
+		var companyName sql.NullString
+
+		if err := rows.Scan(&p.ID, &p.Name, &companyID, &p.CreatedAt, &companyName); err != nil {
+			return nil, err
+		}
+
+		if companyID.Valid {
+			p.CompanyID = &companyID.Int64
+		}
+		p.CompanyName = companyName.String
+
+		projects = append(projects, p)
+	}
+	return projects, rows.Err()
+}
+
 func (r *ProjectRepo) GetOrphans() ([]models.Project, error) {
 	rows, err := r.db.Query(`
 		SELECT id, name, company_id, created_at
@@ -153,11 +189,32 @@ func (r *ProjectRepo) Update(id int64, name string) error {
 	return err
 }
 
+// SetCompany moves a project to the end of another company's projects
 func (r *ProjectRepo) SetCompany(id int64, companyID *int64) error {
-	_, err := r.db.Exec("UPDATE projects SET company_id = ? WHERE id = ?", companyID, id)
+	_, err := r.db.Exec(
+		"UPDATE projects SET company_id = ?, sort_order = "+nextSortOrder+" WHERE id = ?",
+		companyID, companyID, id,
+	)
 	return err
 }
 
+// nextSortOrder selects the sort order after the last project of the company
+// given as its parameter, so new projects don't jump ahead of ordered ones
+const nextSortOrder = "(SELECT COALESCE(MAX(sort_order), 0) + 1 FROM projects WHERE company_id IS ?)"
+
+// SetSortOrder stores the manual order of projects, e.g. within a company.
+// The position of each ID in ids, counting from 1, becomes its sort order.
+func (r *ProjectRepo) SetSortOrder(ids []int64) error {
+	return WithTx(r.db, func(tx *sql.Tx) error {
+		for i, id := range ids {
+			if _, err := tx.Exec("UPDATE projects SET sort_order = ? WHERE id = ?", i+1, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (r *ProjectRepo) Delete(id int64) error {
 	_, err := r.db.Exec("DELETE FROM projects WHERE id = ?", id)
 	return err
@@ -168,6 +225,8 @@ type ProjectWithStats struct {
 	RepoCount   int
 	TaskCount   int
 	CommitCount int
+	Insertions  int // lines added by the commits of the project's repos
+	Deletions   int
 }
 
 func (r *ProjectRepo) GetAllWithStats() ([]ProjectWithStats, error) {
@@ -176,14 +235,18 @@ func (r *ProjectRepo) GetAllWithStats() ([]ProjectWithStats, error) {
 			p.id, p.name, p.company_id, p.created_at, c.name,
 			COUNT(DISTINCT r.id) as repo_count,
 			COUNT(DISTINCT t.id) as task_count,
-			COUNT(DISTINCT rc.id) as commit_count
+			COUNT(DISTINCT rc.id) as commit_count,
+			(SELECT COALESCE(SUM(pc.insertions), 0) FROM raw_commits pc JOIN repos cr ON cr.id = pc.repo_id
+			 WHERE cr.project_id = p.id) as insertions,
+			(SELECT COALESCE(SUM(pc.deletions), 0) FROM raw_commits pc JOIN repos cr ON cr.id = pc.repo_id
+			 WHERE cr.project_id = p.id) as deletions
 		FROM projects p
 		LEFT JOIN companies c ON c.id = p.company_id
 		LEFT JOIN repos r ON r.project_id = p.id
 		LEFT JOIN tasks t ON t.project_id = p.id
 		LEFT JOIN raw_commits rc ON rc.repo_id = r.id
 		GROUP BY p.id
-		ORDER BY c.name, p.name
+		ORDER BY c.name, p.sort_order, p.name
 	`
 	rows, err := r.db.Query(query)
 	if err != nil {
@@ -199,7 +262,7 @@ func (r *ProjectRepo) GetAllWithStats() ([]ProjectWithStats, error) {
 
 		if err := rows.Scan(
 			&p.ID, &p.Name, &companyID, &p.CreatedAt, &companyName,
-			&p.RepoCount, &p.TaskCount, &p.CommitCount,
+			&p.RepoCount, &p.TaskCount, &p.CommitCount, &p.Insertions, &p.Deletions,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/emilianohg/anchorman/internal/db"
+)
+
+func TestProjectGetAllWithStats(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	company, err := NewCompanyRepo(database).Create("Acme Corp")
+	must(err)
+	projects := NewProjectRepo(database)
+	web, err := projects.Create("Web", &company.ID)
+	must(err)
+	_, err = projects.Create("API", &company.ID)
+	must(err)
+
+	repos := NewRepoRepo(database)
+	frontend, err := repos.Create("/src/frontend", &web.ID)
+	must(err)
+	backend, err := repos.Create("/src/backend", &web.ID)
+	must(err)
+
+	commits := NewCommitRepo(database)
+	at := time.Date(2025, 5, 13, 12, 0, 0, 0, time.UTC)
+	var ids []int64
+	for _, c := range []struct {
+		repo                  int64
+		hash                  string
+		insertions, deletions int
+	}{
+		{frontend.ID, "aaa111", 10, 2},
+		{frontend.ID, "aaa222", 5, 1},
+		{backend.ID, "bbb111", 100, 50},
+	} {
+		commit, err := commits.Create(c.repo, c.hash, "change", "Ada Lovelace <ada@example.com>", "main", []string{"main.go"}, c.insertions, c.deletions, at)
+		must(err)
+		ids = append(ids, commit.ID)
+	}
+
+	// Several tasks per project must not count the commits more than once
+	tasks := NewTaskRepo(database)
+	for _, description := range []string{"Added dark mode", "Fixed contrast", "Updated the API client"} {
+		_, err := tasks.Create(web.ID, description, ids, at, 1, "")
+		must(err)
+	}
+
+	stats, err := projects.GetAllWithStats()
+	must(err)
+
+	type summary struct {
+		Name                  string
+		Repos, Tasks, Commits int
+		Insertions, Deletions int
+	}
+	var got []summary
+	for _, p := range stats {
+		got = append(got, summary{p.Name, p.RepoCount, p.TaskCount, p.CommitCount, p.Insertions, p.Deletions})
+	}
+	want := []summary{
+		{"Web", 2, 3, 3, 115, 53},
+		{"API", 0, 0, 0, 0, 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAllWithStats() = %+v, want %+v", got, want)
+	}
+}
@@ -214,6 +214,9 @@ type RepoWithStats struct {
 	models.Repo
 	CommitCount           int
 	UnprocessedCommitCount int
+
+	Insertions int // lines added by the repo's commits
+	Deletions  int
 }
 
 func (r *RepoRepo) GetAllWithStats() ([]RepoWithStats, error) {
@@ -221,7 +224,9 @@ func (r *RepoRepo) GetAllWithStats() ([]RepoWithStats, error) {
 		SELECT
 			r.id, r.path, r.project_id, r.created_at, p.name, c.name,
 			COUNT(rc.id) as commit_count,
-			SUM(CASE WHEN rc.processed = 0 THEN 1 ELSE 0 END) as unprocessed_count
+			SUM(CASE WHEN rc.processed = 0 THEN 1 ELSE 0 END) as unprocessed_count,
+			COALESCE(SUM(rc.insertions), 0) as insertions,
+			COALESCE(SUM(rc.deletions), 0) as deletions
 		FROM repos r
 		LEFT JOIN projects p ON p.id = r.project_id
 		LEFT JOIN companies c ON c.id = p.company_id
@@ -245,7 +250,7 @@ func (r *RepoRepo) GetAllWithStats() ([]RepoWithStats, error) {
 
 		if err := rows.Scan(
 			&repo.ID, &repo.Path, &projectID, &repo.CreatedAt, &projectName, &companyName,
-			&repo.CommitCount, &unprocessedCount,
+			&repo.CommitCount, &unprocessedCount, &repo.Insertions, &repo.Deletions,
 		); err != nil {
 			return nil, err
 		}
@@ -17,16 +17,30 @@ func NewTaskRepo(db *sql.DB) *TaskRepo {
 	return &TaskRepo{db: db}
 }
 
-func (r *TaskRepo) Create(projectID int64, description string, sourceCommits []int64, taskDate time.Time, estimatedHours float64) (*models.Task, error) {
+// Create stores a task. An empty category is stored as "other".
+func (r *TaskRepo) Create(projectID int64, description string, sourceCommits []int64, taskDate time.Time, estimatedHours float64, category string) (*models.Task, error) {
+	return r.create(r.db, projectID, description, sourceCommits, taskDate, estimatedHours, category)
+}
+
+// CreateTx is Create within the transaction tx
+func (r *TaskRepo) CreateTx(tx *sql.Tx, projectID int64, description string, sourceCommits []int64, taskDate time.Time, estimatedHours float64, category string) (*models.Task, error) {
+	return r.create(tx, projectID, description, sourceCommits, taskDate, estimatedHours, category)
+}
+
+func (r *TaskRepo) create(q querier, projectID int64, description string, sourceCommits []int64, taskDate time.Time, estimatedHours float64, category string) (*models.Task, error) {
 	commitsJSON, err := json.Marshal(sourceCommits)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := r.db.Exec(`
-		INSERT INTO tasks (project_id, description, source_commits, task_date, estimated_hours)
-		VALUES (?, ?, ?, ?, ?)
-	`, projectID, description, string(commitsJSON), taskDate, estimatedHours)
+	if category == "" {
+		category = models.CategoryOther
+	}
+
+	result, err := q.Exec(`
+		INSERT INTO tasks (project_id, description, source_commits, task_date, estimated_hours, category)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, projectID, description, string(commitsJSON), taskDate, estimatedHours, category)
 	if err != nil {
 		return nil, err
 	}
@@ -36,20 +50,24 @@ func (r *TaskRepo) Create(projectID int64, description string, sourceCommits []i
 		return nil, err
 	}
 
-	return r.GetByID(id)
+	return r.getByID(q, id)
 }
 
 func (r *TaskRepo) GetByID(id int64) (*models.Task, error) {
+	return r.getByID(r.db, id)
+}
+
+func (r *TaskRepo) getByID(q querier, id int64) (*models.Task, error) {
 	var t models.Task
 	var commitsJSON string
 
-	err := r.db.QueryRow(`
-		SELECT t.id, t.project_id, t.description, t.source_commits, t.task_date, t.estimated_hours, t.created_at, p.name
+	err := q.QueryRow(`
+		SELECT t.id, t.project_id, t.description, t.source_commits, t.task_date, t.estimated_hours, t.category, t.created_at, p.name
 		FROM tasks t
 		JOIN projects p ON p.id = t.project_id
 		WHERE t.id = ?
 	`, id).Scan(
-		&t.ID, &t.ProjectID, &t.Description, &commitsJSON, &t.TaskDate, &t.EstimatedHours, &t.CreatedAt, &t.ProjectName,
+		&t.ID, &t.ProjectID, &t.Description, &commitsJSON, &t.TaskDate, &t.EstimatedHours, &t.Category, &t.CreatedAt, &t.ProjectName,
 	)
 
 	if err == sql.ErrNoRows {
@@ -68,7 +86,7 @@ func (r *TaskRepo) GetByID(id int64) (*models.Task, error) {
 
 func (r *TaskRepo) GetByProjectAndDateRange(projectID int64, from, to time.Time) ([]models.Task, error) {
 	rows, err := r.db.Query(`
-		SELECT t.id, t.project_id, t.description, t.source_commits, t.task_date, t.estimated_hours, t.created_at, p.name
+		SELECT t.id, t.project_id, t.description, t.source_commits, t.task_date, t.estimated_hours, t.category, t.created_at, p.name
 		FROM tasks t
 		JOIN projects p ON p.id = t.project_id
 		WHERE t.project_id = ? AND t.task_date >= ? AND t.task_date <= ?
@@ -84,7 +102,7 @@ func (r *TaskRepo) GetByProjectAndDateRange(projectID int64, from, to time.Time)
 
 func (r *TaskRepo) GetByCompanyAndDateRange(companyID int64, from, to time.Time) ([]models.Task, error) {
 	rows, err := r.db.Query(`
-		SELECT t.id, t.project_id, t.description, t.source_commits, t.task_date, t.estimated_hours, t.created_at, p.name
+		SELECT t.id, t.project_id, t.description, t.source_commits, t.task_date, t.estimated_hours, t.category, t.created_at, p.name
 		FROM tasks t
 		JOIN projects p ON p.id = t.project_id
 		WHERE p.company_id = ? AND t.task_date >= ? AND t.task_date <= ?
@@ -100,7 +118,7 @@ func (r *TaskRepo) GetByCompanyAndDateRange(companyID int64, from, to time.Time)
 
 func (r *TaskRepo) GetByDateRange(from, to time.Time) ([]models.Task, error) {
 	rows, err := r.db.Query(`
-		SELECT t.id, t.project_id, t.description, t.source_commits, t.task_date, t.estimated_hours, t.created_at, p.name
+		SELECT t.id, t.project_id, t.description, t.source_commits, t.task_date, t.estimated_hours, t.category, t.created_at, p.name
 		FROM tasks t
 		JOIN projects p ON p.id = t.project_id
 		WHERE t.task_date >= ? AND t.task_date <= ?
@@ -114,6 +132,40 @@ func (r *TaskRepo) GetByDateRange(from, to time.Time) ([]models.Task, error) {
 	return r.scanTasks(rows)
 }
 
+// GetByCommitIDs returns the tasks derived from any of the given commits
+func (r *TaskRepo) GetByCommitIDs(commitIDs []int64) ([]models.Task, error) {
+	if len(commitIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := ""
+	args := make([]interface{}, len(commitIDs))
+	for i, id := range commitIDs {
+		if i > 0 {
+			placeholders += ","
+		}
+		placeholders += "?"
+		args[i] = id
+	}
+
+	rows, err := r.db.Query(`
+		SELECT t.id, t.project_id, t.description, t.source_commits, t.task_date, t.estimated_hours, t.category, t.created_at, p.name
+		FROM tasks t
+		JOIN projects p ON p.id = t.project_id
+		WHERE EXISTS (
+			SELECT 1 FROM json_each(t.source_commits)
+			WHERE json_each.value IN (`+placeholders+`)
+		)
+		ORDER BY p.name, t.task_date ASC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.scanTasks(rows)
+}
+
 func (r *TaskRepo) scanTasks(rows *sql.Rows) ([]models.Task, error) {
 	var tasks []models.Task
 	for rows.Next() {
@@ -121,7 +173,7 @@ func (r *TaskRepo) scanTasks(rows *sql.Rows) ([]models.Task, error) {
 		var commitsJSON string
 
 		if err := rows.Scan(
-			&t.ID, &t.ProjectID, &t.Description, &commitsJSON, &t.TaskDate, &t.EstimatedHours, &t.CreatedAt, &t.ProjectName,
+			&t.ID, &t.ProjectID, &t.Description, &commitsJSON, &t.TaskDate, &t.EstimatedHours, &t.Category, &t.CreatedAt, &t.ProjectName,
 		); err != nil {
 			return nil, err
 		}
@@ -135,8 +187,27 @@ func (r *TaskRepo) scanTasks(rows *sql.Rows) ([]models.Task, error) {
 	return tasks, rows.Err()
 }
 
+// Update changes the editable fields of a task; its source commits are kept
+func (r *TaskRepo) Update(id, projectID int64, description string, taskDate time.Time, estimatedHours float64) error {
+	_, err := r.db.Exec(`
+		UPDATE tasks
+		SET project_id = ?, description = ?, task_date = ?, estimated_hours = ?
+		WHERE id = ?
+	`, projectID, description, taskDate, estimatedHours, id)
+	return err
+}
+
 func (r *TaskRepo) Delete(id int64) error {
-	_, err := r.db.Exec("DELETE FROM tasks WHERE id = ?", id)
+	return r.delete(r.db, id)
+}
+
+// DeleteTx is Delete within the transaction tx
+func (r *TaskRepo) DeleteTx(tx *sql.Tx, id int64) error {
+	return r.delete(tx, id)
+}
+
+func (r *TaskRepo) delete(q querier, id int64) error {
+	_, err := q.Exec("DELETE FROM tasks WHERE id = ?", id)
 	return err
 }
 
@@ -147,8 +218,17 @@ func (r *TaskRepo) DeleteByProjectID(projectID int64) error {
 
 // DeleteTasksWithCommit deletes tasks where source_commits contains the given commit ID
 func (r *TaskRepo) DeleteTasksWithCommit(commitID int64) (int, error) {
+	return r.deleteTasksWithCommit(r.db, commitID)
+}
+
+// DeleteTasksWithCommitTx is DeleteTasksWithCommit within the transaction tx
+func (r *TaskRepo) DeleteTasksWithCommitTx(tx *sql.Tx, commitID int64) (int, error) {
+	return r.deleteTasksWithCommit(tx, commitID)
+}
+
+func (r *TaskRepo) deleteTasksWithCommit(q querier, commitID int64) (int, error) {
 	// SQLite JSON query to find tasks containing this commit ID
-	result, err := r.db.Exec(`
+	result, err := q.Exec(`
 		DELETE FROM tasks
 		WHERE EXISTS (
 			SELECT 1 FROM json_each(source_commits)
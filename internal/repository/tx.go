@@ -0,0 +1,26 @@
+package repository
+
+import "database/sql"
+
+// querier is implemented by both *sql.DB and *sql.Tx, so the same queries can
+// run inside or outside a transaction
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// WithTx runs fn in a transaction, committing when it returns nil and rolling
+// back otherwise
+func WithTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
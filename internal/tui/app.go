@@ -19,6 +19,7 @@ const (
 	ScreenRepos
 	ScreenReports
 	ScreenProcess
+	ScreenTasks
 )
 
 type App struct {
@@ -35,6 +36,7 @@ type App struct {
 	repos     *screens.Repos
 	reports   *screens.Reports
 	process   *screens.Process
+	tasks     *screens.Tasks
 
 	// Navigation context
 	selectedCompanyID *int64
@@ -56,6 +58,7 @@ func (a *App) Init() tea.Cmd {
 	a.repos = screens.NewRepos(a.db)
 	a.reports = screens.NewReports(a.db, a.cfg)
 	a.process = screens.NewProcess(a.db, a.cfg)
+	a.tasks = screens.NewTasks(a.db, a.cfg)
 
 	return a.dashboard.Init()
 }
@@ -82,6 +85,7 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.repos.SetSize(msg.Width, msg.Height)
 		a.reports.SetSize(msg.Width, msg.Height)
 		a.process.SetSize(msg.Width, msg.Height)
+		a.tasks.SetSize(msg.Width, msg.Height)
 
 	case screens.NavigateMsg:
 		return a.handleNavigation(msg)
@@ -102,6 +106,8 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmd = a.reports.Update(msg)
 	case ScreenProcess:
 		cmd = a.process.Update(msg)
+	case ScreenTasks:
+		cmd = a.tasks.Update(msg)
 	}
 
 	return a, cmd
@@ -135,6 +141,9 @@ func (a *App) handleNavigation(msg screens.NavigateMsg) (tea.Model, tea.Cmd) {
 	case "process":
 		a.currentScreen = ScreenProcess
 		return a, a.process.Init()
+	case "tasks":
+		a.currentScreen = ScreenTasks
+		return a, a.tasks.Init()
 	}
 	return a, nil
 }
@@ -155,6 +164,8 @@ func (a *App) View() string {
 		content = a.reports.View()
 	case ScreenProcess:
 		content = a.process.View()
+	case ScreenTasks:
+		content = a.tasks.View()
 	}
 
 	return lipgloss.NewStyle().
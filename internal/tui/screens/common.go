@@ -30,6 +30,16 @@ func NavigateWithProject(screen string, projectID int64) tea.Cmd {
 	}
 }
 
+// scrollWindow returns the part of lines that fits in visible lines, keeping the
+// focused line in the middle when possible
+func scrollWindow(lines []string, focus, visible int) []string {
+	if visible <= 0 || visible >= len(lines) {
+		return lines
+	}
+	start := min(max(0, focus-visible/2), len(lines)-visible)
+	return lines[start : start+visible]
+}
+
 // RefreshMsg is sent when data should be refreshed
 type RefreshMsg struct{}
 
@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/emilianohg/anchorman/internal/db"
+	"github.com/emilianohg/anchorman/internal/locale"
 	"github.com/emilianohg/anchorman/internal/repository"
 )
 
@@ -177,6 +178,8 @@ func (d *Dashboard) Update(msg tea.Msg) tea.Cmd {
 			return Navigate("reports")
 		case "o":
 			return Navigate("repos")
+		case "t":
+			return Navigate("tasks")
 		}
 	}
 
@@ -233,11 +236,12 @@ func (d *Dashboard) View() string {
 		b.WriteString(SubtitleStyle.Render("Companies"))
 		b.WriteString("\n")
 		for _, c := range d.companies {
-			b.WriteString(fmt.Sprintf("  %s - %d projects, %d repos, %d tasks\n",
+			b.WriteString(fmt.Sprintf("  %s - %d projects, %d repos, %d tasks, %s\n",
 				NormalStyle.Render(c.Name),
 				c.ProjectCount,
 				c.RepoCount,
 				c.TaskCount,
+				locale.Default().Churn(c.Insertions, c.Deletions),
 			))
 		}
 	} else {
@@ -247,7 +251,7 @@ func (d *Dashboard) View() string {
 	b.WriteString("\n")
 
 	// Help
-	help := "[p] Process commits  [c] Companies  [o] Repos  [r] Reports  [q] Quit"
+	help := "[p] Process commits  [t] Tasks  [c] Companies  [o] Repos  [r] Reports  [q] Quit"
 	b.WriteString(HelpStyle.Render(help))
 
 	return b.String()
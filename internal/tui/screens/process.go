@@ -1,16 +1,24 @@
 package screens
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/emilianohg/anchorman/internal/agent"
 	"github.com/emilianohg/anchorman/internal/config"
+	"github.com/emilianohg/anchorman/internal/daterange"
 	"github.com/emilianohg/anchorman/internal/models"
+	"github.com/emilianohg/anchorman/internal/processor"
 	"github.com/emilianohg/anchorman/internal/repository"
 )
 
@@ -18,46 +26,99 @@ type processMode int
 
 const (
 	processModeSelectRange processMode = iota
+	processModeCustomRange
+	processModeScope
 	processModeConfirm
 	processModeProcessing
+	processModeReview
+	processModeReviewEdit
+	processModeReviewDiscard
 	processModeComplete
 )
 
-type processRange int
+// processPresets are the date ranges offered after "All unprocessed"
+var processPresets = []daterange.Preset{
+	daterange.Last7Days,
+	daterange.Last30Days,
+	daterange.LastMonth,
+	daterange.ThisQuarter,
+	daterange.LastQuarter,
+	daterange.YearToDate,
+	daterange.Custom,
+}
 
-const (
-	processRangeAll processRange = iota
-	processRangeLast7Days
-	processRangeLast30Days
-)
+// processRangeLabel returns the label of the range list entry at index i
+func processRangeLabel(i int, reprocess bool) string {
+	if i == 0 {
+		if reprocess {
+			return "All time"
+		}
+		return "All unprocessed"
+	}
+	return processPresets[i-1].String()
+}
 
-var processRangeLabels = []string{
-	"All unprocessed",
-	"Last 7 days",
-	"Last 30 days",
+// processScope is a choice of projects whose tasks are reprocessed
+type processScope struct {
+	label     string
+	companyID *int64
+	projectID *int64
 }
 
 type Process struct {
 	db     *sql.DB
 	cfg    *config.Config
+	cal    daterange.Calendar
 	width  int
 	height int
 
 	mode           processMode
 	rangeCursor    int
-	selectedRange  processRange
+	rangeFrom      time.Time // zero = all unprocessed
+	rangeTo        time.Time
+	picker         rangePicker
 	unprocessed    int
 	commitsToProcess []models.RawCommit
-	tasksCreated   int
-	currentProject string
+	result         *processor.Result
+	cancelled      bool
+	cancel         context.CancelFunc // stops a running job
+	cancelling     bool
+	events         chan tea.Msg // progress and completion of a running job
+	running        []string                  // projects currently being processed
+	finished       []processor.ProjectResult // projects finished so far in a running job
+	total          int                       // projects in a running job
 	loading        bool
 	err            error
+
+	agentName string // agent used for the next job
+
+	// Reprocessing replaces existing tasks instead of processing new commits
+	reprocess   bool
+	scopes      []processScope
+	scopeCursor int
+	replacement *processor.Replacement
+
+	// Review of proposed tasks before they are saved
+	review       bool // keep the job's tasks for review instead of saving them
+	reviewCursor int
+	editHours    bool // the input edits the hours instead of the description
+	input        textinput.Model
+	notice       string // validation or save error shown in review mode
 }
 
 func NewProcess(db *sql.DB, cfg *config.Config) *Process {
+	cal := daterange.NewCalendar(cfg)
+
+	ti := textinput.New()
+	ti.CharLimit = 200
+	ti.Width = 60
+
 	return &Process{
-		db:  db,
-		cfg: cfg,
+		db:     db,
+		cfg:    cfg,
+		cal:    cal,
+		picker: newRangePicker(cal),
+		input:  ti,
 	}
 }
 
@@ -77,20 +138,41 @@ type processCommitsMsg struct {
 }
 
 type processCompleteMsg struct {
-	tasksCreated int
-	err          error
+	result    *processor.Result
+	cancelled bool
+	err       error
 }
 
 type processProgressMsg struct {
-	project string
+	progress processor.Progress
+}
+
+type processSavedMsg struct {
+	err error
+}
+
+type processScopesMsg struct {
+	scopes []processScope
+	err    error
+}
+
+type processReplacementMsg struct {
+	replacement *processor.Replacement
+	err         error
 }
 
 func (p *Process) Init() tea.Cmd {
 	p.mode = processModeSelectRange
 	p.loading = true
 	p.err = nil
-	p.tasksCreated = 0
-	p.currentProject = ""
+	p.result = nil
+	p.cancelled = false
+	p.running = nil
+	p.review = false
+	p.notice = ""
+	p.reprocess = false
+	p.replacement = nil
+	p.agentName = p.cfg.DefaultAgent
 	return p.loadCount
 }
 
@@ -101,112 +183,207 @@ func (p *Process) loadCount() tea.Msg {
 }
 
 func (p *Process) loadCommits() tea.Msg {
-	commitRepo := repository.NewCommitRepo(p.db)
-
-	var commits []models.RawCommit
-	var err error
+	opts := processor.Options{From: p.rangeFrom, To: p.rangeTo}
+	commits, err := processor.LoadCommits(p.db, opts)
+	return processCommitsMsg{commits: commits, err: err}
+}
 
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+// loadRange loads what the selected range affects: the unprocessed commits, or
+// when reprocessing, the projects to choose from
+func (p *Process) loadRange() tea.Cmd {
+	p.loading = true
+	if p.reprocess {
+		return p.loadScopes
+	}
+	return p.loadCommits
+}
 
-	switch p.selectedRange {
-	case processRangeAll:
-		commits, err = commitRepo.GetUnprocessed()
-	case processRangeLast7Days:
-		from := today.AddDate(0, 0, -7)
-		to := today.Add(24*time.Hour - time.Second)
-		commits, err = commitRepo.GetUnprocessedInDateRange(from, to)
-	case processRangeLast30Days:
-		from := today.AddDate(0, 0, -30)
-		to := today.Add(24*time.Hour - time.Second)
-		commits, err = commitRepo.GetUnprocessedInDateRange(from, to)
+func (p *Process) loadScopes() tea.Msg {
+	companies, err := repository.NewCompanyRepo(p.db).GetAll()
+	if err != nil {
+		return processScopesMsg{err: err}
+	}
+	projects, err := repository.NewProjectRepo(p.db).GetAll()
+	if err != nil {
+		return processScopesMsg{err: err}
 	}
 
-	return processCommitsMsg{commits: commits, err: err}
+	scopes := []processScope{{label: "All projects"}}
+	for _, c := range companies {
+		scopes = append(scopes, processScope{label: "Company: " + c.Name, companyID: &c.ID})
+	}
+	for _, proj := range projects {
+		label := "Project: " + proj.Name
+		if proj.CompanyName != "" {
+			label += fmt.Sprintf(" (%s)", proj.CompanyName)
+		}
+		scopes = append(scopes, processScope{label: label, projectID: &proj.ID})
+	}
+	return processScopesMsg{scopes: scopes}
 }
 
-func (p *Process) runProcessing() tea.Msg {
-	if len(p.commitsToProcess) == 0 {
-		return processCompleteMsg{tasksCreated: 0}
-	}
+func (p *Process) loadReplacement() tea.Msg {
+	scope := p.scopes[p.scopeCursor]
+	opts := processor.Options{From: p.rangeFrom, To: p.rangeTo, CompanyID: scope.companyID, ProjectID: scope.projectID}
+	replacement, err := processor.PlanReplacement(p.db, p.cal, opts)
+	return processReplacementMsg{replacement: replacement, err: err}
+}
 
-	// Group commits by project
-	commitsByProject := make(map[int64][]models.RawCommit)
-	projectNames := make(map[int64]string)
+// agentChoices lists the agents that can be picked: the default, the configured
+// ones and the built-in ones
+func agentChoices(cfg *config.Config) []string {
+	names := []string{cfg.DefaultAgent}
+	seen := map[string]bool{cfg.DefaultAgent: true}
 
-	repoRepo := repository.NewRepoRepo(p.db)
+	var configured []string
+	for name := range cfg.Agents {
+		configured = append(configured, name)
+	}
+	sort.Strings(configured)
 
-	for _, c := range p.commitsToProcess {
-		repo, err := repoRepo.GetByID(c.RepoID)
-		if err != nil || repo == nil {
-			continue
+	for _, name := range append(configured, "claude", "codex", "heuristic") {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
 		}
+	}
+	return names
+}
 
-		if repo.ProjectID == nil {
-			// Skip orphan repos
-			continue
+// nextAgent selects the agent after the current one
+func (p *Process) nextAgent() {
+	choices := agentChoices(p.cfg)
+	for i, name := range choices {
+		if name == p.agentName {
+			p.agentName = choices[(i+1)%len(choices)]
+			return
 		}
+	}
+	p.agentName = choices[0]
+}
 
-		commitsByProject[*repo.ProjectID] = append(commitsByProject[*repo.ProjectID], c)
-		projectNames[*repo.ProjectID] = repo.ProjectName
+// keptNote tells what happens to the commits of proposals that aren't saved
+func (p *Process) keptNote() string {
+	if p.replacement != nil {
+		return "Their previous tasks are kept"
+	}
+	return "Their commits stay unprocessed"
+}
+
+// startProcessing runs the processor in the background and streams its progress
+// to the screen. When replacement is set, its tasks are deleted as their
+// replacements are saved. It can be stopped with cancelProcessing, which keeps
+// the tasks of batches that were already saved.
+func (p *Process) startProcessing(commits []models.RawCommit, replacement *processor.Replacement) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.cancelling = false
+	p.running = nil
+	p.finished = nil
+	p.total = 0
+
+	// Room for the start and finish of every project plus the completion, so the
+	// workers never wait for the screen to read their progress
+	events := make(chan tea.Msg, 2*len(commits)+1)
+	p.events = events
+
+	go func() {
+		defer cancel()
+		events <- p.runProcessing(ctx, commits, replacement, events)
+	}()
+
+	return waitForEvent(events)
+}
+
+// waitForEvent delivers the next progress or completion message of a running job
+func waitForEvent(events <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-events
+	}
+}
+
+func (p *Process) cancelProcessing() {
+	if p.cancel != nil {
+		p.cancel()
+		p.cancelling = true
+	}
+}
+
+func (p *Process) runProcessing(ctx context.Context, commits []models.RawCommit, replacement *processor.Replacement, events chan<- tea.Msg) tea.Msg {
+	if len(commits) == 0 {
+		return processCompleteMsg{result: &processor.Result{}}
 	}
 
 	// Get agent
-	ag, err := agent.New(p.cfg.DefaultAgent)
+	ag, err := agent.New(p.agentName, p.cfg)
 	if err != nil {
 		return processCompleteMsg{err: err}
 	}
 
-	taskRepo := repository.NewTaskRepo(p.db)
-	commitRepo := repository.NewCommitRepo(p.db)
-	totalTasks := 0
+	prompts, err := agent.LoadPromptTemplates(p.cfg)
+	if err != nil {
+		return processCompleteMsg{err: err}
+	}
 
-	// Process each project
-	for projectID, commits := range commitsByProject {
-		projectName := projectNames[projectID]
-		if projectName == "" {
-			projectName = "Unknown"
-		}
+	proc := processor.New(p.db, ag, p.cal).
+		WithConcurrency(p.cfg.Concurrency).
+		WithChunking(processor.ChunkingFromConfig(p.cfg)).
+		WithReview(p.review).
+		WithPrompts(prompts).
+		WithReplacement(replacement)
+	proc.OnProgress(func(progress processor.Progress) {
+		events <- processProgressMsg{progress: progress}
+	})
+
+	result, err := proc.ProcessCommits(ctx, commits, processor.Options{})
+	if errors.Is(err, context.Canceled) {
+		return processCompleteMsg{result: result, cancelled: true}
+	}
+	if err != nil {
+		return processCompleteMsg{err: err}
+	}
 
-		// Call agent
-		tasks, err := ag.Process(projectName, commits)
-		if err != nil {
-			return processCompleteMsg{err: fmt.Errorf("failed to process %s: %w", projectName, err)}
-		}
+	return processCompleteMsg{result: result}
+}
 
-		// Get commit IDs
-		var commitIDs []int64
-		for _, c := range commits {
-			commitIDs = append(commitIDs, c.ID)
-		}
+// saveReview stores the reviewed tasks
+func (p *Process) saveReview() tea.Msg {
+	return processSavedMsg{err: processor.Save(p.db, p.cal, p.result)}
+}
 
-		// Determine task date (use the most recent commit date)
-		var taskDate time.Time
-		for _, c := range commits {
-			if c.CommittedAt.After(taskDate) {
-				taskDate = c.CommittedAt
+func (p *Process) Update(msg tea.Msg) tea.Cmd {
+	// In custom range mode, pass messages to the range picker first
+	if p.mode == processModeCustomRange {
+		if _, ok := msg.(tea.KeyMsg); ok {
+			done, cancelled, cmd := p.picker.Update(msg)
+			switch {
+			case done:
+				p.rangeFrom, p.rangeTo, _ = p.picker.Range()
+				return p.loadRange()
+			case cancelled:
+				p.mode = processModeSelectRange
 			}
+			return cmd
 		}
+	}
 
-		// Create tasks
-		for _, task := range tasks {
-			_, err := taskRepo.Create(projectID, task.Description, commitIDs, taskDate, task.EstimatedHours)
-			if err != nil {
-				return processCompleteMsg{err: fmt.Errorf("failed to create task: %w", err)}
+	// While editing a reviewed task, pass messages to the text input first
+	if p.mode == processModeReviewEdit {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "enter":
+				return p.handleEditKey()
+			case "esc":
+				p.mode = processModeReview
+				p.input.Blur()
+				return nil
 			}
-			totalTasks++
-		}
-
-		// Mark commits as processed
-		if err := commitRepo.MarkProcessed(commitIDs); err != nil {
-			return processCompleteMsg{err: fmt.Errorf("failed to mark commits processed: %w", err)}
 		}
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		return cmd
 	}
 
-	return processCompleteMsg{tasksCreated: totalTasks}
-}
-
-func (p *Process) Update(msg tea.Msg) tea.Cmd {
 	switch msg := msg.(type) {
 	case processCountMsg:
 		p.loading = false
@@ -221,19 +398,63 @@ func (p *Process) Update(msg tea.Msg) tea.Cmd {
 		p.mode = processModeConfirm
 		return nil
 
+	case processScopesMsg:
+		p.loading = false
+		p.err = msg.err
+		p.scopes = msg.scopes
+		p.scopeCursor = 0
+		p.mode = processModeScope
+		return nil
+
+	case processReplacementMsg:
+		p.loading = false
+		p.err = msg.err
+		p.replacement = msg.replacement
+		p.mode = processModeConfirm
+		return nil
+
 	case processCompleteMsg:
 		p.loading = false
+		p.cancel = nil
+		p.events = nil
 		p.err = msg.err
-		p.tasksCreated = msg.tasksCreated
+		p.result = msg.result
+		p.cancelled = msg.cancelled
 		if msg.err == nil {
 			p.mode = processModeComplete
+			if p.review && hasProposals(p.result) {
+				p.mode = processModeReview
+				p.reviewCursor = 0
+				p.notice = ""
+			}
 		}
 		return nil
 
-	case processProgressMsg:
-		p.currentProject = msg.project
+	case processSavedMsg:
+		p.loading = false
+		if msg.err != nil {
+			p.notice = fmt.Sprintf("Failed to save: %v", msg.err)
+			return nil
+		}
+		p.mode = processModeComplete
 		return nil
 
+	case processProgressMsg:
+		p.total = msg.progress.Total
+		name := msg.progress.Project.ProjectName
+		if msg.progress.Done {
+			p.finished = append(p.finished, msg.progress.Project)
+			for i, running := range p.running {
+				if running == name {
+					p.running = append(p.running[:i], p.running[i+1:]...)
+					break
+				}
+			}
+		} else {
+			p.running = append(p.running, name)
+		}
+		return waitForEvent(p.events)
+
 	case RefreshMsg:
 		return p.Init()
 
@@ -248,8 +469,16 @@ func (p *Process) handleKey(msg tea.KeyMsg) tea.Cmd {
 	switch p.mode {
 	case processModeSelectRange:
 		return p.handleRangeKey(msg)
+	case processModeScope:
+		return p.handleScopeKey(msg)
 	case processModeConfirm:
 		return p.handleConfirmKey(msg)
+	case processModeProcessing:
+		return p.handleProcessingKey(msg)
+	case processModeReview:
+		return p.handleReviewKey(msg)
+	case processModeReviewDiscard:
+		return p.handleDiscardKey(msg)
 	case processModeComplete:
 		return p.handleCompleteKey(msg)
 	}
@@ -263,25 +492,68 @@ func (p *Process) handleRangeKey(msg tea.KeyMsg) tea.Cmd {
 			p.rangeCursor--
 		}
 	case "down", "j":
-		if p.rangeCursor < len(processRangeLabels)-1 {
+		if p.rangeCursor < len(processPresets) {
 			p.rangeCursor++
 		}
 	case "enter":
-		p.selectedRange = processRange(p.rangeCursor)
-		p.loading = true
-		return p.loadCommits
+		p.rangeFrom, p.rangeTo = time.Time{}, time.Time{}
+		if p.rangeCursor > 0 {
+			preset := processPresets[p.rangeCursor-1]
+			if preset == daterange.Custom {
+				p.picker.Start(p.cal.Range(daterange.LastMonth))
+				p.mode = processModeCustomRange
+				return textinput.Blink
+			}
+			p.rangeFrom, p.rangeTo = p.cal.Range(preset)
+		}
+		return p.loadRange()
+	case "R":
+		p.reprocess = !p.reprocess
 	case "q", "esc":
 		return Navigate("dashboard")
 	}
 	return nil
 }
 
+func (p *Process) handleScopeKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		if p.scopeCursor > 0 {
+			p.scopeCursor--
+		}
+	case "down", "j":
+		if p.scopeCursor < len(p.scopes)-1 {
+			p.scopeCursor++
+		}
+	case "enter":
+		p.loading = true
+		return p.loadReplacement
+	case "esc":
+		p.mode = processModeSelectRange
+	case "q":
+		return Navigate("dashboard")
+	}
+	return nil
+}
+
 func (p *Process) handleConfirmKey(msg tea.KeyMsg) tea.Cmd {
 	switch msg.String() {
-	case "enter", "y":
+	case "enter", "y", "r":
+		if p.reprocess {
+			if len(p.replacement.Tasks) == 0 {
+				return nil
+			}
+			p.review = msg.String() == "r"
+			p.mode = processModeProcessing
+			p.loading = true
+			return p.startProcessing(p.replacement.Commits, p.replacement)
+		}
+		p.review = msg.String() == "r"
 		p.mode = processModeProcessing
 		p.loading = true
-		return p.runProcessing
+		return p.startProcessing(p.commitsToProcess, nil)
+	case "a":
+		p.nextAgent()
 	case "esc", "n":
 		p.mode = processModeSelectRange
 	case "q":
@@ -290,8 +562,183 @@ func (p *Process) handleConfirmKey(msg tea.KeyMsg) tea.Cmd {
 	return nil
 }
 
+func (p *Process) handleProcessingKey(msg tea.KeyMsg) tea.Cmd {
+	if p.loading {
+		if msg.String() == "esc" {
+			p.cancelProcessing()
+		}
+		return nil
+	}
+
+	// Processing failed
+	switch msg.String() {
+	case "enter":
+		return p.Init()
+	case "q", "esc":
+		return Navigate("dashboard")
+	}
+	return nil
+}
+
+// reviewRef locates a proposed task within the result being reviewed
+type reviewRef struct {
+	project  int
+	proposal int
+	task     int
+}
+
+// reviewRefs lists the proposed tasks in display order
+func (p *Process) reviewRefs() []reviewRef {
+	var refs []reviewRef
+	if p.result == nil {
+		return refs
+	}
+	for i, pr := range p.result.Projects {
+		for j, proposal := range pr.Proposals {
+			for k := range proposal.Tasks {
+				refs = append(refs, reviewRef{project: i, proposal: j, task: k})
+			}
+		}
+	}
+	return refs
+}
+
+// proposal returns the proposal ref points into
+func (p *Process) proposal(ref reviewRef) *processor.Proposal {
+	return &p.result.Projects[ref.project].Proposals[ref.proposal]
+}
+
+// proposedTask returns the task ref points to
+func (p *Process) proposedTask(ref reviewRef) *processor.ProposedTask {
+	return &p.proposal(ref).Tasks[ref.task]
+}
+
+// hasProposals reports whether any batch of the result awaits review
+func hasProposals(result *processor.Result) bool {
+	if result == nil {
+		return false
+	}
+	for _, pr := range result.Projects {
+		if len(pr.Proposals) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Process) handleReviewKey(msg tea.KeyMsg) tea.Cmd {
+	refs := p.reviewRefs()
+	p.notice = ""
+
+	switch msg.String() {
+	case "up", "k":
+		if p.reviewCursor > 0 {
+			p.reviewCursor--
+		}
+	case "down", "j":
+		if p.reviewCursor < len(refs)-1 {
+			p.reviewCursor++
+		}
+	case "e", "h":
+		if len(refs) > 0 {
+			task := p.proposedTask(refs[p.reviewCursor])
+			p.editHours = msg.String() == "h"
+			if p.editHours {
+				p.input.SetValue(strconv.FormatFloat(task.EstimatedHours, 'f', -1, 64))
+			} else {
+				p.input.SetValue(task.Description)
+			}
+			p.input.CursorEnd()
+			p.input.Focus()
+			p.mode = processModeReviewEdit
+			return textinput.Blink
+		}
+	case "m":
+		// Merge the selected task with the next one of the same project
+		if p.reviewCursor+1 < len(refs) {
+			ref, next := refs[p.reviewCursor], refs[p.reviewCursor+1]
+			if ref.project != next.project {
+				p.notice = "Only tasks of the same project can be merged"
+				return nil
+			}
+			merged := processor.MergeTasks(*p.proposedTask(ref), *p.proposedTask(next))
+			*p.proposedTask(ref) = merged
+			p.removeProposedTask(next)
+		}
+	case "s":
+		if len(refs) > 0 {
+			ref := refs[p.reviewCursor]
+			proposal := p.proposal(ref)
+			first, second := proposal.Tasks[ref.task].Split()
+			proposal.Tasks[ref.task] = first
+			proposal.Tasks = append(proposal.Tasks[:ref.task+1],
+				append([]processor.ProposedTask{second}, proposal.Tasks[ref.task+1:]...)...)
+		}
+	case "d":
+		if len(refs) > 0 {
+			p.removeProposedTask(refs[p.reviewCursor])
+			if p.reviewCursor >= len(refs)-1 {
+				p.reviewCursor = max(0, len(refs)-2)
+			}
+		}
+	case "enter":
+		p.loading = true
+		return p.saveReview
+	case "esc", "q":
+		p.mode = processModeReviewDiscard
+	}
+	return nil
+}
+
+func (p *Process) removeProposedTask(ref reviewRef) {
+	proposal := p.proposal(ref)
+	proposal.Tasks = append(proposal.Tasks[:ref.task], proposal.Tasks[ref.task+1:]...)
+}
+
+// handleEditKey applies the description or hours typed for the selected task
+func (p *Process) handleEditKey() tea.Cmd {
+	task := p.proposedTask(p.reviewRefs()[p.reviewCursor])
+	value := strings.TrimSpace(p.input.Value())
+
+	if p.editHours {
+		hours, err := strconv.ParseFloat(strings.TrimSuffix(value, "h"), 64)
+		if err != nil || hours <= 0 {
+			p.notice = fmt.Sprintf("Invalid hours: %q", value)
+			return nil
+		}
+		task.EstimatedHours = hours
+	} else {
+		if value == "" {
+			p.notice = "The description can't be empty"
+			return nil
+		}
+		task.Description = value
+	}
+
+	p.notice = ""
+	p.mode = processModeReview
+	p.input.Blur()
+	return nil
+}
+
+func (p *Process) handleDiscardKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "y", "Y":
+		return p.Init()
+	case "n", "N", "esc":
+		p.mode = processModeReview
+	}
+	return nil
+}
+
 func (p *Process) handleCompleteKey(msg tea.KeyMsg) tea.Cmd {
 	switch msg.String() {
+	case "r":
+		if p.result != nil && len(p.result.FailedCommits()) > 0 {
+			p.mode = processModeProcessing
+			p.loading = true
+			return p.startProcessing(p.result.FailedCommits(), p.replacement)
+		}
 	case "enter", "q", "esc":
 		return Navigate("dashboard")
 	}
@@ -301,14 +748,32 @@ func (p *Process) handleCompleteKey(msg tea.KeyMsg) tea.Cmd {
 func (p *Process) View() string {
 	var b strings.Builder
 
-	b.WriteString(TitleStyle.Render("PROCESS COMMITS"))
+	title := "PROCESS COMMITS"
+	if p.reprocess {
+		title = "REPROCESS TASKS"
+	}
+	b.WriteString(TitleStyle.Render(title))
 	b.WriteString("\n\n")
 
 	if p.loading && p.mode == processModeProcessing {
-		b.WriteString(fmt.Sprintf("Processing with %s...\n", p.cfg.DefaultAgent))
-		if p.currentProject != "" {
-			b.WriteString(fmt.Sprintf("Current: %s\n", p.currentProject))
+		if p.cancelling {
+			b.WriteString("Cancelling, waiting for the agent to stop...\n")
+			return b.String()
 		}
+		b.WriteString(fmt.Sprintf("Processing with %s...", p.agentName))
+		if p.total > 0 {
+			b.WriteString(DimStyle.Render(fmt.Sprintf(" %d of %d projects done", len(p.finished), p.total)))
+		}
+		b.WriteString("\n\n")
+		for _, project := range p.finished {
+			b.WriteString(projectResultLine(project))
+			b.WriteString("\n")
+		}
+		for _, name := range p.running {
+			b.WriteString(fmt.Sprintf("  %s: working...\n", name))
+		}
+		b.WriteString("\n")
+		b.WriteString(HelpStyle.Render("[esc] Cancel"))
 		return b.String()
 	}
 
@@ -327,8 +792,18 @@ func (p *Process) View() string {
 	switch p.mode {
 	case processModeSelectRange:
 		return p.viewSelectRange(&b)
+	case processModeCustomRange:
+		b.WriteString(p.picker.View())
+		return b.String()
+	case processModeScope:
+		return p.viewScope(&b)
 	case processModeConfirm:
+		if p.reprocess {
+			return p.viewReprocessConfirm(&b)
+		}
 		return p.viewConfirm(&b)
+	case processModeReview, processModeReviewEdit, processModeReviewDiscard:
+		return p.viewReview(&b)
 	case processModeComplete:
 		return p.viewComplete(&b)
 	}
@@ -339,16 +814,21 @@ func (p *Process) View() string {
 func (p *Process) viewSelectRange(b *strings.Builder) string {
 	b.WriteString(fmt.Sprintf("Unprocessed commits: %s\n\n", WarningStyle.Render(fmt.Sprintf("%d", p.unprocessed))))
 
-	if p.unprocessed == 0 {
+	if p.unprocessed == 0 && !p.reprocess {
 		b.WriteString(SuccessStyle.Render("All commits are processed!"))
 		b.WriteString("\n\n")
-		b.WriteString(HelpStyle.Render("[q] Back"))
+		b.WriteString(HelpStyle.Render("[R] Reprocess existing tasks  [q] Back"))
 		return b.String()
 	}
 
-	b.WriteString("Select commits to process:\n\n")
+	if p.reprocess {
+		b.WriteString("Select the tasks to replace:\n\n")
+	} else {
+		b.WriteString("Select commits to process:\n\n")
+	}
 
-	for i, label := range processRangeLabels {
+	for i := 0; i <= len(processPresets); i++ {
+		label := processRangeLabel(i, p.reprocess)
 		cursor := "  "
 		style := NormalStyle
 		if i == p.rangeCursor {
@@ -360,8 +840,80 @@ func (p *Process) viewSelectRange(b *strings.Builder) string {
 	}
 
 	b.WriteString("\n")
-	b.WriteString(fmt.Sprintf("Agent: %s\n\n", DimStyle.Render(p.cfg.DefaultAgent)))
-	b.WriteString(HelpStyle.Render("[enter] Select  [q] Back"))
+	b.WriteString(fmt.Sprintf("Agent: %s\n\n", DimStyle.Render(p.agentName)))
+	if p.reprocess {
+		b.WriteString(HelpStyle.Render("[enter] Select  [R] Process new commits  [q] Back"))
+	} else {
+		b.WriteString(HelpStyle.Render("[enter] Select  [R] Reprocess existing tasks  [q] Back"))
+	}
+
+	return b.String()
+}
+
+func (p *Process) viewScope(b *strings.Builder) string {
+	b.WriteString("Reprocess tasks of:\n\n")
+
+	for i, scope := range p.scopes {
+		cursor := "  "
+		style := NormalStyle
+		if i == p.scopeCursor {
+			cursor = "> "
+			style = SelectedStyle
+		}
+		b.WriteString(style.Render(cursor + scope.label))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render("[enter] Select  [esc] Back"))
+	return b.String()
+}
+
+// viewReprocessConfirm previews the tasks that reprocessing replaces
+func (p *Process) viewReprocessConfirm(b *strings.Builder) string {
+	r := p.replacement
+
+	b.WriteString(p.scopes[p.scopeCursor].label)
+	b.WriteString("\n")
+	if !p.rangeFrom.IsZero() {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("Period: %s - %s",
+			p.rangeFrom.Format("Jan 02, 2006"), p.rangeTo.Format("Jan 02, 2006"))))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if len(r.Tasks) == 0 {
+		b.WriteString(DimStyle.Render("No tasks to reprocess. Tasks added by hand are never replaced."))
+		b.WriteString("\n\n")
+		b.WriteString(HelpStyle.Render("[esc] Back"))
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("These %d tasks (%s) will be replaced by summarizing their %d commits again:\n\n",
+		len(r.Tasks), formatHours(r.Hours()), len(r.Commits)))
+
+	var lines []string
+	for _, t := range r.Tasks {
+		note := ""
+		if r.Related(t) {
+			note = " (shares commits with a selected task)"
+		}
+		lines = append(lines, fmt.Sprintf("  %s  %s  [%s] %s%s",
+			t.TaskDate.Format("Jan 02"), t.ProjectName, formatHours(t.EstimatedHours), t.Description, note))
+	}
+	visible := 0
+	if p.height > 0 {
+		visible = max(5, p.height-16)
+	}
+	b.WriteString(DimStyle.Render(strings.Join(scrollWindow(lines, 0, visible), "\n")))
+	if visible > 0 && visible < len(lines) {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("\n  ... and %d more", len(lines)-visible)))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(fmt.Sprintf("Agent: %s\n\n", p.agentName))
+	b.WriteString("Proceed? (y/n)\n\n")
+	b.WriteString(HelpStyle.Render("[y/enter] Reprocess  [r] Reprocess and review  [a] Change agent  [n/esc] Cancel"))
 
 	return b.String()
 }
@@ -382,7 +934,13 @@ func (p *Process) viewConfirm(b *strings.Builder) string {
 		}
 	}
 
-	b.WriteString(fmt.Sprintf("Ready to process %d commits\n\n", len(p.commitsToProcess)))
+	b.WriteString(fmt.Sprintf("Ready to process %d commits\n", len(p.commitsToProcess)))
+	if !p.rangeFrom.IsZero() {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("Period: %s - %s",
+			p.rangeFrom.Format("Jan 02, 2006"), p.rangeTo.Format("Jan 02, 2006"))))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
 
 	if orphanCount > 0 {
 		b.WriteString(WarningStyle.Render(fmt.Sprintf("Note: %d commits are from orphan repos and will be skipped.\n", orphanCount)))
@@ -390,18 +948,172 @@ func (p *Process) viewConfirm(b *strings.Builder) string {
 		b.WriteString("\n")
 	}
 
-	b.WriteString(fmt.Sprintf("Agent: %s\n\n", p.cfg.DefaultAgent))
+	b.WriteString(fmt.Sprintf("Agent: %s\n\n", p.agentName))
 	b.WriteString("Proceed? (y/n)\n\n")
-	b.WriteString(HelpStyle.Render("[y/enter] Process  [n/esc] Cancel"))
+	b.WriteString(HelpStyle.Render("[y/enter] Process  [r] Process and review  [a] Change agent  [n/esc] Cancel"))
+
+	return b.String()
+}
+
+func (p *Process) viewReview(b *strings.Builder) string {
+	refs := p.reviewRefs()
+
+	var tasks, commits int
+	var hours float64
+	for _, pr := range p.result.Projects {
+		for _, proposal := range pr.Proposals {
+			commits += len(proposal.Batch.Commits)
+			for _, task := range proposal.Tasks {
+				tasks++
+				hours += task.EstimatedHours
+			}
+		}
+	}
+
+	b.WriteString(fmt.Sprintf("Review %d proposed tasks (%s) for %d commits\n", tasks, formatHours(hours), commits))
+	b.WriteString(DimStyle.Render("Nothing is saved until you press enter."))
+	b.WriteString("\n\n")
+
+	var lines []string
+	focus := 0
+	i := 0
+	for _, pr := range p.result.Projects {
+		for _, proposal := range pr.Proposals {
+			lines = append(lines, fmt.Sprintf("%s - %s",
+				proposal.Batch.ProjectName, proposal.Batch.Day.Format("Jan 02, 2006")))
+			if len(proposal.Tasks) == 0 {
+				lines = append(lines, DimStyle.Render(fmt.Sprintf("    No tasks; its %d commits will be marked processed", len(proposal.Batch.Commits))))
+			}
+
+			for _, task := range proposal.Tasks {
+				cursor := "  "
+				style := NormalStyle
+				if i < len(refs) && i == p.reviewCursor {
+					cursor = "> "
+					style = SelectedStyle
+					focus = len(lines)
+				}
+				lines = append(lines, style.Render(fmt.Sprintf("%s[%s] %s", cursor, formatHours(task.EstimatedHours), task.Description)))
+
+				if i == p.reviewCursor {
+					for _, c := range task.Commits {
+						lines = append(lines, DimStyle.Render(fmt.Sprintf("      %s %s", shortHash(c.Hash), firstLine(c.Message))))
+					}
+				} else {
+					var hashes []string
+					for _, c := range task.Commits {
+						hashes = append(hashes, shortHash(c.Hash))
+					}
+					lines = append(lines, DimStyle.Render("      "+strings.Join(hashes, ", ")))
+				}
+				i++
+			}
+		}
+	}
+
+	visible := 0
+	if p.height > 0 {
+		// Leave room for the title, summary, input and help
+		visible = max(5, p.height-14)
+	}
+	b.WriteString(strings.Join(scrollWindow(lines, focus, visible), "\n"))
+	b.WriteString("\n\n")
+
+	if failed := p.result.Failed(); len(failed) > 0 {
+		b.WriteString(WarningStyle.Render(fmt.Sprintf("%d projects failed. %s.", len(failed), p.keptNote())))
+		b.WriteString("\n\n")
+	}
+
+	if p.notice != "" {
+		b.WriteString(ErrorStyle.Render(p.notice))
+		b.WriteString("\n\n")
+	}
+
+	switch p.mode {
+	case processModeReviewEdit:
+		if p.editHours {
+			b.WriteString("Estimated hours:\n")
+		} else {
+			b.WriteString("Description:\n")
+		}
+		b.WriteString(p.input.View())
+		b.WriteString("\n\n")
+		b.WriteString(HelpStyle.Render("[enter] Apply  [esc] Cancel"))
+	case processModeReviewDiscard:
+		b.WriteString(WarningStyle.Render(fmt.Sprintf("Discard all proposed tasks? %s. (y/n)", p.keptNote())))
+	default:
+		b.WriteString(HelpStyle.Render("[e] Edit  [h] Hours  [m] Merge with next  [s] Split  [d] Delete  [enter] Save  [esc] Discard"))
+	}
 
 	return b.String()
 }
 
+// formatHours renders hours with up to two decimals, e.g. "1.5h" or "0.25h"
+func formatHours(hours float64) string {
+	return strconv.FormatFloat(math.Round(hours*100)/100, 'f', -1, 64) + "h"
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+// firstLine returns the subject line of a commit message
+func firstLine(message string) string {
+	subject, _, _ := strings.Cut(message, "\n")
+	return subject
+}
+
 func (p *Process) viewComplete(b *strings.Builder) string {
-	b.WriteString(SuccessStyle.Render("Processing complete!"))
+	failed := p.result.Failed()
+
+	switch {
+	case p.cancelled:
+		b.WriteString(WarningStyle.Render("Processing cancelled"))
+	case len(failed) > 0:
+		b.WriteString(WarningStyle.Render(fmt.Sprintf("Processing finished with %d failed projects", len(failed))))
+	default:
+		b.WriteString(SuccessStyle.Render("Processing complete!"))
+	}
 	b.WriteString("\n\n")
-	b.WriteString(fmt.Sprintf("Created %d tasks\n\n", p.tasksCreated))
-	b.WriteString(HelpStyle.Render("[enter] Done"))
+
+	for _, project := range p.result.Projects {
+		b.WriteString(projectResultLine(project))
+		b.WriteString("\n")
+	}
+	if len(p.result.Projects) > 0 {
+		b.WriteString("\n")
+	}
+
+	b.WriteString(fmt.Sprintf("Created %d tasks\n", p.result.TasksCreated))
+	if p.cancelled {
+		b.WriteString(DimStyle.Render("Remaining commits are still unprocessed."))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if failedCommits := p.result.FailedCommits(); len(failedCommits) > 0 {
+		b.WriteString(HelpStyle.Render(fmt.Sprintf("[r] Retry failed (%d commits)  [enter] Done", len(failedCommits))))
+	} else {
+		b.WriteString(HelpStyle.Render("[enter] Done"))
+	}
 
 	return b.String()
 }
+
+// projectResultLine renders the outcome of a single project
+func projectResultLine(project processor.ProjectResult) string {
+	if project.Err != nil {
+		return fmt.Sprintf("  %s %s: %v",
+			ErrorStyle.Render("FAILED"), project.ProjectName, project.Err)
+	}
+	if len(project.Proposals) > 0 {
+		return fmt.Sprintf("  %s %s: %d commits -> %d tasks to review",
+			SuccessStyle.Render("OK"), project.ProjectName, project.Commits, project.ProposedTasks())
+	}
+	return fmt.Sprintf("  %s %s: %d commits -> %d tasks",
+		SuccessStyle.Render("OK"), project.ProjectName, project.Commits, project.TasksCreated)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/emilianohg/anchorman/internal/locale"
 	"github.com/emilianohg/anchorman/internal/repository"
 )
 
@@ -177,6 +178,10 @@ func (p *Projects) handleListKey(msg tea.KeyMsg) tea.Cmd {
 		if len(p.projects) > 0 {
 			p.mode = projectsModeDelete
 		}
+	case "K", "shift+up":
+		return p.reorder(-1)
+	case "J", "shift+down":
+		return p.reorder(1)
 	case "m":
 		if len(p.projects) > 0 && len(p.companies) > 0 {
 			p.mode = projectsModeMove
@@ -195,6 +200,35 @@ func (p *Projects) handleListKey(msg tea.KeyMsg) tea.Cmd {
 	return nil
 }
 
+// reorder moves the selected project up or down within its company.
+// The resulting order is used by reports with the "manual" project order.
+func (p *Projects) reorder(delta int) tea.Cmd {
+	if p.companyFilter == nil {
+		p.message = "Open a company's projects to reorder them"
+		return nil
+	}
+
+	target := p.cursor + delta
+	if target < 0 || target >= len(p.projects) {
+		return nil
+	}
+
+	p.projects[p.cursor], p.projects[target] = p.projects[target], p.projects[p.cursor]
+	p.cursor = target
+
+	ids := make([]int64, len(p.projects))
+	for i, proj := range p.projects {
+		ids[i] = proj.ID
+	}
+
+	repo := repository.NewProjectRepo(p.db)
+	if err := repo.SetSortOrder(ids); err != nil {
+		p.err = err
+		return p.loadData
+	}
+	return nil
+}
+
 func (p *Projects) handleInputKey() tea.Cmd {
 	name := strings.TrimSpace(p.input.Value())
 	if name == "" {
@@ -364,11 +398,12 @@ func (p *Projects) View() string {
 				company = DimStyle.Render(fmt.Sprintf("(%s)", proj.CompanyName))
 			}
 
-			line := fmt.Sprintf("%s%s %s - %d repos",
+			line := fmt.Sprintf("%s%s %s - %d repos, %s",
 				cursor,
 				proj.Name,
 				company,
 				proj.RepoCount,
+				locale.Default().Churn(proj.Insertions, proj.Deletions),
 			)
 			b.WriteString(style.Render(line))
 			b.WriteString("\n")
@@ -377,6 +412,9 @@ func (p *Projects) View() string {
 	}
 
 	help := "[a] Add  [e] Edit  [d] Delete  [m] Move  [enter] View repos  [q] Back"
+	if p.companyFilter != nil {
+		help = "[a] Add  [e] Edit  [d] Delete  [m] Move  [J/K] Reorder  [enter] View repos  [q] Back"
+	}
 	b.WriteString(HelpStyle.Render(help))
 
 	return b.String()
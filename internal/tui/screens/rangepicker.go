@@ -0,0 +1,119 @@
+package screens
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/emilianohg/anchorman/internal/daterange"
+)
+
+// rangePicker lets the user type a custom from/to date range
+type rangePicker struct {
+	cal   daterange.Calendar
+	from  textinput.Model
+	to    textinput.Model
+	focus int // 0 = from, 1 = to
+	err   error
+}
+
+func newRangePicker(cal daterange.Calendar) rangePicker {
+	from := textinput.New()
+	from.Placeholder = "YYYY-MM-DD"
+	from.CharLimit = 10
+	from.Width = 12
+
+	to := textinput.New()
+	to.Placeholder = "YYYY-MM-DD"
+	to.CharLimit = 10
+	to.Width = 12
+
+	return rangePicker{cal: cal, from: from, to: to}
+}
+
+// Start prefills the inputs with the given range and focuses the start date
+func (rp *rangePicker) Start(from, to time.Time) {
+	rp.from.SetValue(from.Format(daterange.DateLayout))
+	rp.to.SetValue(to.Format(daterange.DateLayout))
+	rp.focus = 0
+	rp.err = nil
+	rp.from.Focus()
+	rp.to.Blur()
+}
+
+// Update handles a key press. It returns done when a valid range was
+// confirmed and cancelled when the user backed out.
+func (rp *rangePicker) Update(msg tea.Msg) (done, cancelled bool, cmd tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "esc":
+			rp.from.Blur()
+			rp.to.Blur()
+			return false, true, nil
+		case "tab", "shift+tab", "up", "down":
+			rp.toggleFocus()
+			return false, false, nil
+		case "enter":
+			if rp.focus == 0 {
+				rp.toggleFocus()
+				return false, false, nil
+			}
+			if _, _, err := rp.Range(); err != nil {
+				rp.err = err
+				return false, false, nil
+			}
+			rp.from.Blur()
+			rp.to.Blur()
+			return true, false, nil
+		}
+	}
+
+	rp.err = nil
+	if rp.focus == 0 {
+		rp.from, cmd = rp.from.Update(msg)
+	} else {
+		rp.to, cmd = rp.to.Update(msg)
+	}
+	return false, false, cmd
+}
+
+func (rp *rangePicker) toggleFocus() {
+	if rp.focus == 0 {
+		rp.focus = 1
+		rp.from.Blur()
+		rp.to.Focus()
+	} else {
+		rp.focus = 0
+		rp.to.Blur()
+		rp.from.Focus()
+	}
+}
+
+// Range validates the typed dates and returns the selected range
+func (rp *rangePicker) Range() (time.Time, time.Time, error) {
+	return rp.cal.Parse(
+		strings.TrimSpace(rp.from.Value()),
+		strings.TrimSpace(rp.to.Value()),
+	)
+}
+
+func (rp *rangePicker) View() string {
+	var b strings.Builder
+
+	b.WriteString("Custom date range:\n\n")
+	b.WriteString("From: " + rp.from.View() + "\n")
+	b.WriteString("To:   " + rp.to.View() + "\n")
+
+	if rp.err != nil {
+		b.WriteString("\n")
+		b.WriteString(ErrorStyle.Render(rp.err.Error()))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render("[tab] Switch field  [enter] Confirm  [esc] Cancel"))
+
+	return b.String()
+}
@@ -3,15 +3,16 @@ package screens
 import (
 	"database/sql"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/emilianohg/anchorman/internal/config"
-	"github.com/emilianohg/anchorman/internal/models"
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/locale"
+	"github.com/emilianohg/anchorman/internal/report"
 	"github.com/emilianohg/anchorman/internal/repository"
 )
 
@@ -20,34 +21,31 @@ type reportsMode int
 const (
 	reportsModeSelectCompany reportsMode = iota
 	reportsModeSelectRange
+	reportsModeCustomRange
 	reportsModePreview
 	reportsModeGenerating
 	reportsModeComplete
 )
 
-type dateRange int
-
-const (
-	rangeToday dateRange = iota
-	rangeThisWeek
-	rangeLastWeek
-	rangeThisMonth
-	rangeLast7Days
-	rangeLast30Days
-)
-
-var rangeLabels = []string{
-	"Today",
-	"This Week",
-	"Last Week",
-	"This Month",
-	"Last 7 Days",
-	"Last 30 Days",
+// reportPresets are the date ranges offered when generating a report
+var reportPresets = []daterange.Preset{
+	daterange.Today,
+	daterange.ThisWeek,
+	daterange.LastWeek,
+	daterange.ThisMonth,
+	daterange.LastMonth,
+	daterange.ThisQuarter,
+	daterange.LastQuarter,
+	daterange.YearToDate,
+	daterange.Last7Days,
+	daterange.Last30Days,
+	daterange.Custom,
 }
 
 type Reports struct {
 	db     *sql.DB
 	cfg    *config.Config
+	cal    daterange.Calendar
 	width  int
 	height int
 
@@ -56,20 +54,42 @@ type Reports struct {
 	companyCursor   int
 	rangeCursor     int
 	mode            reportsMode
-	selectedRange   dateRange
-	previewTasks    []models.Task
+	selectedRange   daterange.Preset
+	customFrom      time.Time
+	customTo        time.Time
+	picker          rangePicker
+	preview         *report.Report
 	generatedPath   string
 	loading         bool
 	err             error
 	message         string
 	showTime        bool // toggle to show/hide time estimates
 	showAuthors     bool // toggle to show/hide authors
+	showChanges     bool // toggle to show/hide lines added and removed
+	format          string
+	groupBy         report.GroupBy
+	order           report.Order
+	previewOffset   int // first visible line of the preview
+
+	renderer       report.Renderer // renderer of rendererFormat, reused across frames
+	rendererFormat string
 }
 
 func NewReports(db *sql.DB, cfg *config.Config) *Reports {
+	format := cfg.ReportFormat
+	if format == "" {
+		format = report.Formats[0]
+	}
+
+	cal := daterange.NewCalendar(cfg)
 	return &Reports{
-		db:  db,
-		cfg: cfg,
+		db:      db,
+		cfg:     cfg,
+		cal:     cal,
+		picker:  newRangePicker(cal),
+		format:  format,
+		groupBy: report.GroupByProject,
+		order:   report.OrderName,
 	}
 }
 
@@ -88,8 +108,8 @@ type reportsDataMsg struct {
 }
 
 type previewDataMsg struct {
-	tasks []models.Task
-	err   error
+	report *report.Report
+	err    error
 }
 
 type generateCompleteMsg struct {
@@ -116,165 +136,86 @@ func (r *Reports) loadData() tea.Msg {
 	return reportsDataMsg{companies: companies, err: err}
 }
 
-func (r *Reports) loadPreview() tea.Msg {
+// loadPreview builds the report of the selected company and range in the background
+func (r *Reports) loadPreview() tea.Cmd {
 	if r.companyFilter == nil {
-		return previewDataMsg{err: fmt.Errorf("no company selected")}
-	}
-
-	from, to := r.getDateRange()
-	taskRepo := repository.NewTaskRepo(r.db)
-	tasks, err := taskRepo.GetByCompanyAndDateRange(*r.companyFilter, from, to)
-	if err != nil {
-		return previewDataMsg{err: err}
+		return func() tea.Msg { return previewDataMsg{err: fmt.Errorf("no company selected")} }
 	}
 
-	// Populate authors for each task
-	for i := range tasks {
-		authors, err := taskRepo.GetAuthorsForCommits(tasks[i].SourceCommits)
-		if err == nil {
-			tasks[i].Authors = authors
-		}
+	opts := r.reportOptions()
+	return func() tea.Msg {
+		rep, err := report.Build(r.db, opts)
+		return previewDataMsg{report: rep, err: err}
 	}
-
-	return previewDataMsg{tasks: tasks, err: nil}
 }
 
-func (r *Reports) generateReport() tea.Msg {
-	if r.companyFilter == nil {
-		return generateCompleteMsg{err: fmt.Errorf("no company selected")}
-	}
-
-	// Find company name
-	var companyName string
-	for _, c := range r.companies {
-		if c.ID == *r.companyFilter {
-			companyName = c.Name
-			break
-		}
+// generateReport saves the previewed report in the background. The command works on
+// its own copy of the report, so the preview can keep rendering while it runs.
+func (r *Reports) generateReport() tea.Cmd {
+	if r.preview == nil {
+		return func() tea.Msg { return generateCompleteMsg{err: fmt.Errorf("nothing to generate")} }
 	}
 
-	from, to := r.getDateRange()
-	taskRepo := repository.NewTaskRepo(r.db)
-	tasks, err := taskRepo.GetByCompanyAndDateRange(*r.companyFilter, from, to)
+	renderer, err := r.currentRenderer()
 	if err != nil {
-		return generateCompleteMsg{err: err}
+		return func() tea.Msg { return generateCompleteMsg{err: err} }
 	}
 
-	// Populate authors for each task if needed
-	if r.showAuthors {
-		for i := range tasks {
-			authors, err := taskRepo.GetAuthorsForCommits(tasks[i].SourceCommits)
-			if err == nil {
-				tasks[i].Authors = authors
-			}
-		}
-	}
-
-	// Group tasks by project
-	tasksByProject := make(map[string][]models.Task)
-	for _, t := range tasks {
-		tasksByProject[t.ProjectName] = append(tasksByProject[t.ProjectName], t)
-	}
-
-	// Generate markdown
-	var md strings.Builder
-	md.WriteString(fmt.Sprintf("# %s - Report\n\n", companyName))
-	md.WriteString(fmt.Sprintf("**Period:** %s - %s\n", from.Format("January 02, 2006"), to.Format("January 02, 2006")))
-	md.WriteString(fmt.Sprintf("**Generated:** %s\n\n", time.Now().Format("2006-01-02")))
-	md.WriteString("---\n\n")
-
-	var totalHours float64
-	for projectName, projectTasks := range tasksByProject {
-		md.WriteString(fmt.Sprintf("## %s\n\n", projectName))
-
-		// Show project contributors if authors enabled
-		if r.showAuthors {
-			projectAuthors := r.getProjectAuthors(projectTasks)
-			if len(projectAuthors) > 0 {
-				md.WriteString(fmt.Sprintf("**Contributors:** %s\n\n", strings.Join(projectAuthors, ", ")))
-			}
+	// Save the previewed report so the file matches the preview exactly
+	rep := r.previewReport()
+	output := r.cfg.ReportsOutput
+	return func() tea.Msg {
+		path, err := report.Save(output, rep, renderer)
+		if err != nil {
+			return generateCompleteMsg{err: err}
 		}
-
-		var projectHours float64
-		for _, t := range projectTasks {
-			taskLine := "- " + t.Description
-			if r.showAuthors && len(t.Authors) > 0 {
-				taskLine += " (" + strings.Join(t.Authors, ", ") + ")"
-			}
-			if r.showTime {
-				taskLine += fmt.Sprintf(" (%.1fh)", t.EstimatedHours)
-			}
-			md.WriteString(taskLine + "\n")
-			projectHours += t.EstimatedHours
-		}
-		if r.showTime {
-			md.WriteString(fmt.Sprintf("\n**Subtotal: %.1fh**\n", projectHours))
-		}
-		md.WriteString("\n")
-		totalHours += projectHours
-	}
-
-	md.WriteString("---\n\n")
-	if r.showTime {
-		md.WriteString(fmt.Sprintf("**Total: %.1fh**\n\n", totalHours))
-	}
-	md.WriteString("*Generated by Anchorman*\n")
-
-	// Write file
-	outputDir := r.cfg.ReportsOutput
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return generateCompleteMsg{err: fmt.Errorf("failed to create output directory: %w", err)}
+		return generateCompleteMsg{path: path}
 	}
+}
 
-	slug := strings.ToLower(strings.ReplaceAll(companyName, " ", "-"))
-	filename := fmt.Sprintf("%s_%s_to_%s.md",
-		slug,
-		from.Format("2006-01-02"),
-		to.Format("2006-01-02"),
-	)
-	filepath := filepath.Join(outputDir, filename)
-
-	if err := os.WriteFile(filepath, []byte(md.String()), 0644); err != nil {
-		return generateCompleteMsg{err: fmt.Errorf("failed to write report: %w", err)}
+func (r *Reports) reportOptions() report.Options {
+	from, to := r.getDateRange()
+	return report.Options{
+		CompanyID:   *r.companyFilter,
+		From:        from,
+		To:          to,
+		ShowTime:    r.showTime,
+		ShowAuthors: r.showAuthors,
+		ShowChanges: r.showChanges,
+		GroupBy:     r.groupBy,
+		Order:       r.order,
+		Calendar:    r.cal,
+		Locale:      r.companyLocale(),
 	}
-
-	return generateCompleteMsg{path: filepath}
 }
 
 func (r *Reports) getDateRange() (time.Time, time.Time) {
-	now := time.Now()
-	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-
-	switch r.selectedRange {
-	case rangeToday:
-		return today, today.Add(24*time.Hour - time.Second)
-	case rangeThisWeek:
-		weekday := int(today.Weekday())
-		if weekday == 0 {
-			weekday = 7
-		}
-		start := today.AddDate(0, 0, -weekday+1)
-		return start, today.Add(24*time.Hour - time.Second)
-	case rangeLastWeek:
-		weekday := int(today.Weekday())
-		if weekday == 0 {
-			weekday = 7
-		}
-		end := today.AddDate(0, 0, -weekday)
-		start := end.AddDate(0, 0, -6)
-		return start, end.Add(24*time.Hour - time.Second)
-	case rangeThisMonth:
-		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-		return start, today.Add(24*time.Hour - time.Second)
-	case rangeLast7Days:
-		return today.AddDate(0, 0, -6), today.Add(24*time.Hour - time.Second)
-	case rangeLast30Days:
-		return today.AddDate(0, 0, -29), today.Add(24*time.Hour - time.Second)
+	if r.selectedRange == daterange.Custom {
+		return r.customFrom, r.customTo
 	}
-	return today, today
+	return r.cal.Range(r.selectedRange)
 }
 
 func (r *Reports) Update(msg tea.Msg) tea.Cmd {
+	// In custom range mode, pass messages to the range picker first
+	if r.mode == reportsModeCustomRange {
+		if _, ok := msg.(tea.KeyMsg); ok {
+			done, cancelled, cmd := r.picker.Update(msg)
+			switch {
+			case done:
+				r.customFrom, r.customTo, _ = r.picker.Range()
+				r.selectedRange = daterange.Custom
+				r.mode = reportsModePreview
+				r.previewOffset = 0
+				r.loading = true
+				return r.loadPreview()
+			case cancelled:
+				r.mode = reportsModeSelectRange
+			}
+			return cmd
+		}
+	}
+
 	switch msg := msg.(type) {
 	case reportsDataMsg:
 		r.loading = false
@@ -289,13 +230,14 @@ func (r *Reports) Update(msg tea.Msg) tea.Cmd {
 					break
 				}
 			}
+			r.applyCompanyDefaults()
 		}
 		return nil
 
 	case previewDataMsg:
 		r.loading = false
 		r.err = msg.err
-		r.previewTasks = msg.tasks
+		r.preview = msg.report
 		return nil
 
 	case generateCompleteMsg:
@@ -345,6 +287,7 @@ func (r *Reports) handleCompanyKey(msg tea.KeyMsg) tea.Cmd {
 		if len(r.companies) > 0 {
 			r.companyFilter = &r.companies[r.companyCursor].ID
 			r.mode = reportsModeSelectRange
+			r.applyCompanyDefaults()
 		}
 	case "q", "esc":
 		return Navigate("dashboard")
@@ -359,18 +302,36 @@ func (r *Reports) handleRangeKey(msg tea.KeyMsg) tea.Cmd {
 			r.rangeCursor--
 		}
 	case "down", "j":
-		if r.rangeCursor < len(rangeLabels)-1 {
+		if r.rangeCursor < len(reportPresets)-1 {
 			r.rangeCursor++
 		}
 	case "enter":
-		r.selectedRange = dateRange(r.rangeCursor)
+		if reportPresets[r.rangeCursor] == daterange.Custom {
+			from, to := r.cal.Range(daterange.ThisMonth)
+			if !r.customFrom.IsZero() {
+				from, to = r.customFrom, r.customTo
+			}
+			r.picker.Start(from, to)
+			r.mode = reportsModeCustomRange
+			return textinput.Blink
+		}
+		r.selectedRange = reportPresets[r.rangeCursor]
 		r.mode = reportsModePreview
+		r.previewOffset = 0
 		r.loading = true
-		return r.loadPreview
+		return r.loadPreview()
 	case "t":
 		r.showTime = !r.showTime
 	case "a":
 		r.showAuthors = !r.showAuthors
+	case "c":
+		r.showChanges = !r.showChanges
+	case "f":
+		r.cycleFormat()
+	case "b":
+		r.cycleGroupBy()
+	case "o":
+		r.cycleOrder()
 	case "esc":
 		r.mode = reportsModeSelectCompany
 		r.companyFilter = nil
@@ -385,11 +346,33 @@ func (r *Reports) handlePreviewKey(msg tea.KeyMsg) tea.Cmd {
 	case "enter", "g":
 		r.mode = reportsModeGenerating
 		r.loading = true
-		return r.generateReport
+		return r.generateReport()
 	case "t":
 		r.showTime = !r.showTime
 	case "a":
 		r.showAuthors = !r.showAuthors
+	case "c":
+		r.showChanges = !r.showChanges
+	case "f":
+		r.cycleFormat()
+	case "b":
+		r.cycleGroupBy()
+		r.loading = true
+		return r.loadPreview()
+	case "o":
+		r.cycleOrder()
+		r.loading = true
+		return r.loadPreview()
+	case "up", "k":
+		if r.previewOffset > 0 {
+			r.previewOffset--
+		}
+	case "down", "j":
+		r.previewOffset++
+	case "pgup":
+		r.previewOffset = max(0, r.previewOffset-r.previewLines(r.height))
+	case "pgdown", " ":
+		r.previewOffset += r.previewLines(r.height)
 	case "esc":
 		r.mode = reportsModeSelectRange
 	case "q":
@@ -428,6 +411,8 @@ func (r *Reports) View() string {
 		return r.viewSelectCompany(&b)
 	case reportsModeSelectRange:
 		return r.viewSelectRange(&b)
+	case reportsModeCustomRange:
+		return r.viewCustomRange(&b)
 	case reportsModePreview:
 		return r.viewPreview(&b)
 	case reportsModeComplete:
@@ -471,36 +456,36 @@ func (r *Reports) viewSelectRange(b *strings.Builder) string {
 		}
 	}
 
-	// Show toggle statuses
-	if r.showTime {
-		b.WriteString(SuccessStyle.Render("Time estimates: ON"))
-	} else {
-		b.WriteString(DimStyle.Render("Time estimates: OFF"))
-	}
-	b.WriteString("  ")
-	if r.showAuthors {
-		b.WriteString(SuccessStyle.Render("Authors: ON"))
-	} else {
-		b.WriteString(DimStyle.Render("Authors: OFF"))
-	}
-	b.WriteString("\n\n")
+	r.viewToggles(b)
 
 	b.WriteString("Select date range:\n\n")
 
-	for i, label := range rangeLabels {
+	for i, preset := range reportPresets {
 		cursor := "  "
 		style := NormalStyle
 		if i == r.rangeCursor {
 			cursor = "> "
 			style = SelectedStyle
 		}
-		b.WriteString(style.Render(fmt.Sprintf("%s%s", cursor, label)))
+		b.WriteString(style.Render(fmt.Sprintf("%s%s", cursor, preset)))
 		b.WriteString("\n")
 	}
 
 	b.WriteString("\n")
-	b.WriteString(HelpStyle.Render("[enter] Select  [t] Toggle time  [a] Toggle authors  [esc] Back"))
+	b.WriteString(HelpStyle.Render("[enter] Select  [t] Toggle time  [a] Toggle authors  [c] Toggle changes  [f] Format  [b] Breakdown  [o] Order  [esc] Back"))
+
+	return b.String()
+}
+
+func (r *Reports) viewCustomRange(b *strings.Builder) string {
+	for _, c := range r.companies {
+		if c.ID == *r.companyFilter {
+			b.WriteString(fmt.Sprintf("Company: %s\n\n", SelectedStyle.Render(c.Name)))
+			break
+		}
+	}
 
+	b.WriteString(r.picker.View())
 	return b.String()
 }
 
@@ -515,7 +500,86 @@ func (r *Reports) viewPreview(b *strings.Builder) string {
 	from, to := r.getDateRange()
 	b.WriteString(fmt.Sprintf("Period: %s - %s\n", from.Format("Jan 02"), to.Format("Jan 02, 2006")))
 
-	// Show toggle statuses
+	r.viewToggles(b)
+
+	if r.preview == nil || r.preview.TaskCount == 0 {
+		b.WriteString(WarningStyle.Render("No tasks found for this period."))
+		b.WriteString("\n")
+		b.WriteString(DimStyle.Render("Process some commits first, or select a different date range."))
+		b.WriteString("\n\n")
+		b.WriteString(HelpStyle.Render("[t] Toggle time  [a] Toggle authors  [c] Toggle changes  [f] Format  [b] Breakdown  [o] Order  [esc] Back  [q] Cancel"))
+		return b.String()
+	}
+
+	// Show exactly what will be written to the file
+	content, err := r.renderPreview()
+	if err != nil {
+		b.WriteString(ErrorStyle.Render(fmt.Sprintf("Error: %v", err)))
+		b.WriteString("\n")
+	} else {
+		lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+		visible := r.previewLines(len(lines))
+		if r.previewOffset > len(lines)-visible {
+			r.previewOffset = max(0, len(lines)-visible)
+		}
+
+		b.WriteString(strings.Join(lines[r.previewOffset:r.previewOffset+visible], "\n"))
+		b.WriteString("\n")
+		if visible < len(lines) {
+			b.WriteString(DimStyle.Render(fmt.Sprintf("-- lines %d-%d of %d --",
+				r.previewOffset+1, r.previewOffset+visible, len(lines))))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render("[g/enter] Generate  [j/k] Scroll  [t] Toggle time  [a] Toggle authors  [c] Toggle changes  [f] Format  [b] Breakdown  [o] Order  [esc] Back"))
+
+	return b.String()
+}
+
+// renderPreview renders the previewed report with the current toggles and format
+func (r *Reports) renderPreview() (string, error) {
+	renderer, err := r.currentRenderer()
+	if err != nil {
+		return "", err
+	}
+	return report.RenderString(r.previewReport(), renderer)
+}
+
+// previewReport returns a copy of the previewed report with the current toggles
+func (r *Reports) previewReport() *report.Report {
+	rep := *r.preview
+	rep.ShowTime = r.showTime
+	rep.ShowAuthors = r.showAuthors
+	rep.ShowChanges = r.showChanges
+	return &rep
+}
+
+// currentRenderer returns the renderer of the selected format, building it only
+// when the format or the company changed
+func (r *Reports) currentRenderer() (report.Renderer, error) {
+	if r.renderer == nil || r.rendererFormat != r.format {
+		renderer, err := report.NewRenderer(r.format)
+		if err != nil {
+			return nil, err
+		}
+		r.renderer, r.rendererFormat = renderer, r.format
+	}
+	return r.renderer, nil
+}
+
+// previewLines returns how many report lines fit on screen
+func (r *Reports) previewLines(total int) int {
+	if r.height == 0 {
+		return total
+	}
+	// Leave room for the title, header, toggles and help
+	return min(total, max(5, r.height-12))
+}
+
+// viewToggles shows the status of the time/authors toggles and the output format
+func (r *Reports) viewToggles(b *strings.Builder) {
 	if r.showTime {
 		b.WriteString(SuccessStyle.Render("Time estimates: ON"))
 	} else {
@@ -527,83 +591,105 @@ func (r *Reports) viewPreview(b *strings.Builder) string {
 	} else {
 		b.WriteString(DimStyle.Render("Authors: OFF"))
 	}
-	b.WriteString("\n\n")
-
-	if len(r.previewTasks) == 0 {
-		b.WriteString(WarningStyle.Render("No tasks found for this period."))
-		b.WriteString("\n")
-		b.WriteString(DimStyle.Render("Process some commits first, or select a different date range."))
+	b.WriteString("  ")
+	if r.showChanges {
+		b.WriteString(SuccessStyle.Render("Changes: ON"))
 	} else {
-		b.WriteString(fmt.Sprintf("Found %d tasks:\n\n", len(r.previewTasks)))
+		b.WriteString(DimStyle.Render("Changes: OFF"))
+	}
+	b.WriteString("  ")
+	b.WriteString(fmt.Sprintf("Format: %s", SelectedStyle.Render(r.format)))
+	b.WriteString("  ")
+	b.WriteString(fmt.Sprintf("Group by: %s", SelectedStyle.Render(string(r.groupBy))))
+	b.WriteString("  ")
+	b.WriteString(fmt.Sprintf("Order: %s", SelectedStyle.Render(string(r.order))))
+	b.WriteString("\n\n")
+}
 
-		// Group by project
-		tasksByProject := make(map[string][]models.Task)
-		for _, t := range r.previewTasks {
-			tasksByProject[t.ProjectName] = append(tasksByProject[t.ProjectName], t)
+// companyConfig returns the config of the selected company
+func (r *Reports) companyConfig() config.CompanyConfig {
+	if r.companyFilter == nil {
+		return config.CompanyConfig{}
+	}
+	for _, c := range r.companies {
+		if c.ID == *r.companyFilter {
+			return r.cfg.Company(c.Name)
 		}
+	}
+	return config.CompanyConfig{}
+}
 
-		var totalHours float64
-		for projectName, tasks := range tasksByProject {
-			b.WriteString(SubtitleStyle.Render(projectName))
+// companyTemplate returns the report template configured for the selected company
+func (r *Reports) companyTemplate() string {
+	return r.companyConfig().ReportTemplate
+}
 
-			// Show project contributors if authors enabled
-			if r.showAuthors {
-				projectAuthors := r.getProjectAuthors(tasks)
-				if len(projectAuthors) > 0 {
-					b.WriteString(fmt.Sprintf(" - %s", DimStyle.Render(strings.Join(projectAuthors, ", "))))
-				}
-			}
-			b.WriteString("\n")
+// companyLocale returns the report locale configured for the selected company,
+// falling back to English
+func (r *Reports) companyLocale() *locale.Locale {
+	if l, err := locale.Parse(r.companyConfig().Locale); err == nil {
+		return l
+	}
+	return locale.Default()
+}
 
-			var projectHours float64
-			for _, t := range tasks {
-				taskLine := "  - " + t.Description
-				if r.showAuthors && len(t.Authors) > 0 {
-					taskLine += " (" + strings.Join(t.Authors, ", ") + ")"
-				}
-				if r.showTime {
-					taskLine += fmt.Sprintf(" (%.1fh)", t.EstimatedHours)
-				}
-				b.WriteString(taskLine + "\n")
-				projectHours += t.EstimatedHours
-			}
-			if r.showTime {
-				b.WriteString(DimStyle.Render(fmt.Sprintf("  Subtotal: %.1fh", projectHours)))
-				b.WriteString("\n")
-			}
-			b.WriteString("\n")
-			totalHours += projectHours
-		}
+// formats returns the selectable output formats, including the company template if any
+func (r *Reports) formats() []string {
+	formats := report.Formats
+	if t := r.companyTemplate(); t != "" {
+		formats = append([]string{report.TemplateFormat(t)}, formats...)
+	}
+	return formats
+}
 
-		if r.showTime {
-			b.WriteString(SelectedStyle.Render(fmt.Sprintf("Total: %.1fh", totalHours)))
-			b.WriteString("\n")
-		}
+// applyCompanyDefaults selects the company's template and project order if
+// configured, falling back to the global defaults
+func (r *Reports) applyCompanyDefaults() {
+	r.renderer = nil // another company may use another template
+
+	r.format = r.formats()[0]
+	if r.companyTemplate() == "" && r.cfg.ReportFormat != "" {
+		r.format = r.cfg.ReportFormat
 	}
 
-	b.WriteString("\n")
-	if len(r.previewTasks) > 0 {
-		b.WriteString(HelpStyle.Render("[g/enter] Generate  [t] Toggle time  [a] Toggle authors  [esc] Back"))
-	} else {
-		b.WriteString(HelpStyle.Render("[t] Toggle time  [a] Toggle authors  [esc] Back  [q] Cancel"))
+	r.order = report.OrderName
+	if order, err := report.ParseOrder(r.companyConfig().ReportOrder); err == nil {
+		r.order = order
 	}
+}
 
-	return b.String()
+// cycleFormat switches to the next report output format
+func (r *Reports) cycleFormat() {
+	formats := r.formats()
+	for i, f := range formats {
+		if f == r.format {
+			r.format = formats[(i+1)%len(formats)]
+			return
+		}
+	}
+	r.format = formats[0]
 }
 
-// getProjectAuthors returns unique authors for all tasks in a project
-func (r *Reports) getProjectAuthors(tasks []models.Task) []string {
-	seen := make(map[string]bool)
-	var authors []string
-	for _, t := range tasks {
-		for _, a := range t.Authors {
-			if !seen[a] {
-				seen[a] = true
-				authors = append(authors, a)
-			}
+// cycleGroupBy switches to the next task breakdown
+func (r *Reports) cycleGroupBy() {
+	for i, g := range report.Groupings {
+		if g == r.groupBy {
+			r.groupBy = report.Groupings[(i+1)%len(report.Groupings)]
+			return
+		}
+	}
+	r.groupBy = report.Groupings[0]
+}
+
+// cycleOrder switches to the next project order
+func (r *Reports) cycleOrder() {
+	for i, o := range report.Orders {
+		if o == r.order {
+			r.order = report.Orders[(i+1)%len(report.Orders)]
+			return
 		}
 	}
-	return authors
+	r.order = report.Orders[0]
 }
 
 func (r *Reports) viewComplete(b *strings.Builder) string {
@@ -8,6 +8,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/emilianohg/anchorman/internal/locale"
 	"github.com/emilianohg/anchorman/internal/repository"
 )
 
@@ -314,7 +315,8 @@ func (r *Repos) View() string {
 
 			// Show path on second line when selected
 			if i == r.cursor {
-				b.WriteString(DimStyle.Render(fmt.Sprintf("     %s", repo.Path)))
+				b.WriteString(DimStyle.Render(fmt.Sprintf("     %s - %d commits, %s",
+					repo.Path, repo.CommitCount, locale.Default().Churn(repo.Insertions, repo.Deletions))))
 				b.WriteString("\n")
 			}
 		}
@@ -0,0 +1,539 @@
+package screens
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/emilianohg/anchorman/internal/config"
+	"github.com/emilianohg/anchorman/internal/daterange"
+	"github.com/emilianohg/anchorman/internal/models"
+	"github.com/emilianohg/anchorman/internal/repository"
+)
+
+type tasksMode int
+
+const (
+	tasksModeList tasksMode = iota
+	tasksModeForm
+	tasksModeDelete
+)
+
+// Fields of the task form, in tab order
+const (
+	taskFieldDescription = iota
+	taskFieldHours
+	taskFieldDate
+	taskFieldProject
+	taskFieldCount
+)
+
+type Tasks struct {
+	db     *sql.DB
+	cal    daterange.Calendar
+	width  int
+	height int
+
+	month         time.Time // first day of the month shown
+	tasks         []models.Task
+	projects      []models.Project
+	cursor        int
+	mode          tasksMode
+	editing       *models.Task // task being edited; nil when adding one
+	inputs        []textinput.Model
+	focus         int
+	projectCursor int
+	formErr       error
+	loading       bool
+	err           error
+	message       string
+}
+
+func NewTasks(db *sql.DB, cfg *config.Config) *Tasks {
+	description := textinput.New()
+	description.Placeholder = "e.g. Weekly planning meeting"
+	description.CharLimit = 200
+	description.Width = 50
+
+	hours := textinput.New()
+	hours.Placeholder = "1.5"
+	hours.CharLimit = 6
+	hours.Width = 8
+
+	date := textinput.New()
+	date.Placeholder = "YYYY-MM-DD"
+	date.CharLimit = 10
+	date.Width = 12
+
+	cal := daterange.NewCalendar(cfg)
+	return &Tasks{
+		db:     db,
+		cal:    cal,
+		month:  monthStart(cal.Day(time.Now())),
+		inputs: []textinput.Model{description, hours, date},
+	}
+}
+
+func (t *Tasks) SetSize(width, height int) {
+	t.width = width
+	t.height = height
+}
+
+type tasksDataMsg struct {
+	tasks    []models.Task
+	projects []models.Project
+	err      error
+}
+
+func (t *Tasks) Init() tea.Cmd {
+	t.loading = true
+	t.mode = tasksModeList
+	t.message = ""
+	return t.loadData
+}
+
+// monthStart returns the first day of the month containing day
+func monthStart(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+}
+
+func (t *Tasks) loadData() tea.Msg {
+	taskRepo := repository.NewTaskRepo(t.db)
+	projectRepo := repository.NewProjectRepo(t.db)
+
+	from := t.month
+	to := t.cal.EndOfDay(from.AddDate(0, 1, -1))
+
+	// Task dates are stored with their UTC offset, so the query is padded by a
+	// day and the exact month is checked here
+	candidates, err := taskRepo.GetByDateRange(from.Add(-24*time.Hour), to.Add(24*time.Hour))
+	if err != nil {
+		return tasksDataMsg{err: err}
+	}
+
+	var tasks []models.Task
+	for _, task := range candidates {
+		task.TaskDate = t.cal.In(task.TaskDate)
+		if task.TaskDate.Before(from) || task.TaskDate.After(to) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	projects, err := projectRepo.GetAll()
+	if err != nil {
+		return tasksDataMsg{err: err}
+	}
+	sortByProject(tasks, projects)
+
+	return tasksDataMsg{tasks: tasks, projects: projects}
+}
+
+// sortByProject orders tasks by company, project and date, so the tasks of each
+// project are listed together under one header. Projects with the same name are
+// kept apart by their ID.
+func sortByProject(tasks []models.Task, projects []models.Project) {
+	companies := make(map[int64]string)
+	for _, p := range projects {
+		companies[p.ID] = p.CompanyName
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if ca, cb := companies[a.ProjectID], companies[b.ProjectID]; ca != cb {
+			return ca < cb
+		}
+		if a.ProjectName != b.ProjectName {
+			return a.ProjectName < b.ProjectName
+		}
+		if a.ProjectID != b.ProjectID {
+			return a.ProjectID < b.ProjectID
+		}
+		return a.TaskDate.Before(b.TaskDate)
+	})
+}
+
+// projectCompany returns the name of the project's company, or "" when it has none
+func (t *Tasks) projectCompany(projectID int64) string {
+	for _, p := range t.projects {
+		if p.ID == projectID {
+			return p.CompanyName
+		}
+	}
+	return ""
+}
+
+func (t *Tasks) Update(msg tea.Msg) tea.Cmd {
+	// In the form, pass messages to the focused input first
+	if t.mode == tasksModeForm {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return t.handleFormKey(keyMsg)
+		}
+		if t.focus < len(t.inputs) {
+			var cmd tea.Cmd
+			t.inputs[t.focus], cmd = t.inputs[t.focus].Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	switch msg := msg.(type) {
+	case tasksDataMsg:
+		t.loading = false
+		t.err = msg.err
+		t.tasks = msg.tasks
+		t.projects = msg.projects
+		if t.cursor >= len(t.tasks) {
+			t.cursor = max(0, len(t.tasks)-1)
+		}
+		return nil
+
+	case RefreshMsg:
+		return t.Init()
+
+	case tea.KeyMsg:
+		switch t.mode {
+		case tasksModeList:
+			return t.handleListKey(msg)
+		case tasksModeDelete:
+			return t.handleDeleteKey(msg)
+		}
+	}
+
+	return nil
+}
+
+func (t *Tasks) handleListKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "up", "k":
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case "down", "j":
+		if t.cursor < len(t.tasks)-1 {
+			t.cursor++
+		}
+	case "left", "h", "[":
+		t.month = t.month.AddDate(0, -1, 0)
+		t.cursor = 0
+		return t.loadData
+	case "right", "l", "]":
+		t.month = t.month.AddDate(0, 1, 0)
+		t.cursor = 0
+		return t.loadData
+	case "a":
+		if len(t.projects) == 0 {
+			t.message = "Create a project first"
+			return nil
+		}
+		return t.startForm(nil)
+	case "e", "enter":
+		if len(t.tasks) > 0 {
+			return t.startForm(&t.tasks[t.cursor])
+		}
+	case "d":
+		if len(t.tasks) > 0 {
+			t.mode = tasksModeDelete
+		}
+	case "q", "esc":
+		return Navigate("dashboard")
+	}
+	return nil
+}
+
+// startForm opens the form for editing task, or for a new task when task is nil
+func (t *Tasks) startForm(task *models.Task) tea.Cmd {
+	t.editing = task
+	t.formErr = nil
+	t.message = ""
+	t.mode = tasksModeForm
+
+	projectID := int64(0)
+	if task != nil {
+		t.inputs[taskFieldDescription].SetValue(task.Description)
+		t.inputs[taskFieldHours].SetValue(strconv.FormatFloat(task.EstimatedHours, 'f', -1, 64))
+		t.inputs[taskFieldDate].SetValue(task.TaskDate.Format(daterange.DateLayout))
+		projectID = task.ProjectID
+	} else {
+		// New tasks default to today, or to the first day of another month shown
+		date := t.cal.Day(time.Now())
+		if !monthStart(date).Equal(t.month) {
+			date = t.month
+		}
+		t.inputs[taskFieldDescription].SetValue("")
+		t.inputs[taskFieldHours].SetValue("1")
+		t.inputs[taskFieldDate].SetValue(date.Format(daterange.DateLayout))
+		if len(t.tasks) > 0 {
+			projectID = t.tasks[t.cursor].ProjectID
+		}
+	}
+
+	t.projectCursor = 0
+	for i, p := range t.projects {
+		if p.ID == projectID {
+			t.projectCursor = i
+		}
+	}
+
+	return t.setFocus(taskFieldDescription)
+}
+
+func (t *Tasks) setFocus(field int) tea.Cmd {
+	t.focus = field
+	for i := range t.inputs {
+		if i == field {
+			t.inputs[i].Focus()
+			t.inputs[i].CursorEnd()
+		} else {
+			t.inputs[i].Blur()
+		}
+	}
+	return textinput.Blink
+}
+
+// blurAll removes the focus from every form field
+func (t *Tasks) blurAll() {
+	for i := range t.inputs {
+		t.inputs[i].Blur()
+	}
+}
+
+func (t *Tasks) handleFormKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		t.mode = tasksModeList
+		t.blurAll()
+		return nil
+	case "tab", "down":
+		return t.setFocus((t.focus + 1) % taskFieldCount)
+	case "shift+tab", "up":
+		return t.setFocus((t.focus + taskFieldCount - 1) % taskFieldCount)
+	case "enter":
+		return t.saveForm()
+	}
+
+	if t.focus == taskFieldProject {
+		switch msg.String() {
+		case "left", "h":
+			if t.projectCursor > 0 {
+				t.projectCursor--
+			}
+		case "right", "l":
+			if t.projectCursor < len(t.projects)-1 {
+				t.projectCursor++
+			}
+		}
+		return nil
+	}
+
+	var cmd tea.Cmd
+	t.inputs[t.focus], cmd = t.inputs[t.focus].Update(msg)
+	return cmd
+}
+
+// saveForm validates the form and creates or updates the task
+func (t *Tasks) saveForm() tea.Cmd {
+	description := strings.TrimSpace(t.inputs[taskFieldDescription].Value())
+	if description == "" {
+		t.formErr = fmt.Errorf("description is required")
+		return t.setFocus(taskFieldDescription)
+	}
+
+	hoursValue := strings.TrimSpace(t.inputs[taskFieldHours].Value())
+	hours, err := strconv.ParseFloat(strings.TrimSuffix(hoursValue, "h"), 64)
+	if err != nil || hours <= 0 {
+		t.formErr = fmt.Errorf("invalid hours %q", hoursValue)
+		return t.setFocus(taskFieldHours)
+	}
+
+	dateValue := strings.TrimSpace(t.inputs[taskFieldDate].Value())
+	date, err := t.cal.ParseDate(dateValue)
+	if err != nil {
+		t.formErr = fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", dateValue)
+		return t.setFocus(taskFieldDate)
+	}
+
+	project := t.projects[t.projectCursor]
+	taskRepo := repository.NewTaskRepo(t.db)
+
+	if t.editing != nil {
+		// Keep the time of day of tasks whose date didn't change
+		if dateValue == t.editing.TaskDate.Format(daterange.DateLayout) {
+			date = t.editing.TaskDate
+		}
+		err = taskRepo.Update(t.editing.ID, project.ID, description, date, hours)
+		t.message = "Task updated"
+	} else {
+		// Manual tasks have no source commits
+		_, err = taskRepo.Create(project.ID, description, []int64{}, date, hours, models.CategoryOther)
+		t.message = fmt.Sprintf("Added task to %s", project.Name)
+	}
+	if err != nil {
+		t.message = ""
+		t.formErr = err
+		return nil
+	}
+
+	t.mode = tasksModeList
+	t.blurAll()
+	return t.loadData
+}
+
+func (t *Tasks) handleDeleteKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "y", "Y":
+		repo := repository.NewTaskRepo(t.db)
+		err := repo.Delete(t.tasks[t.cursor].ID)
+		if err != nil {
+			t.err = err
+		} else {
+			t.message = "Task deleted"
+		}
+		t.mode = tasksModeList
+		return t.loadData
+
+	case "n", "N", "esc":
+		t.mode = tasksModeList
+	}
+	return nil
+}
+
+func (t *Tasks) View() string {
+	var b strings.Builder
+
+	b.WriteString(TitleStyle.Render("TASKS"))
+	b.WriteString("\n\n")
+
+	if t.loading {
+		b.WriteString("Loading...\n")
+		return b.String()
+	}
+
+	if t.err != nil {
+		b.WriteString(ErrorStyle.Render(fmt.Sprintf("Error: %v", t.err)))
+		b.WriteString("\n\n")
+		t.err = nil
+	}
+
+	if t.message != "" {
+		b.WriteString(SuccessStyle.Render(t.message))
+		b.WriteString("\n\n")
+	}
+
+	if t.mode == tasksModeForm {
+		return t.viewForm(&b)
+	}
+
+	if t.mode == tasksModeDelete && len(t.tasks) > 0 {
+		task := t.tasks[t.cursor]
+		prompt := fmt.Sprintf("Delete task '%s'? (y/n)", task.Description)
+		if len(task.SourceCommits) > 0 {
+			prompt = fmt.Sprintf("Delete task '%s'? Its commits stay processed. (y/n)", task.Description)
+		}
+		b.WriteString(WarningStyle.Render(prompt))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	var total float64
+	for _, task := range t.tasks {
+		total += task.EstimatedHours
+	}
+	b.WriteString(fmt.Sprintf("%s  %s\n\n",
+		t.month.Format("January 2006"),
+		DimStyle.Render(fmt.Sprintf("%d tasks, %s", len(t.tasks), formatHours(total)))))
+
+	if len(t.tasks) == 0 {
+		b.WriteString(DimStyle.Render("No tasks in this month."))
+		b.WriteString("\n\n")
+	} else {
+		var lines []string
+		focus := 0
+		for i, task := range t.tasks {
+			if i == 0 || task.ProjectID != t.tasks[i-1].ProjectID {
+				header := SubtitleStyle.UnsetMarginBottom().Render(task.ProjectName)
+				if company := t.projectCompany(task.ProjectID); company != "" {
+					header += DimStyle.Render(fmt.Sprintf(" (%s)", company))
+				}
+				lines = append(lines, header)
+			}
+
+			cursor := "  "
+			style := NormalStyle
+			if i == t.cursor {
+				cursor = "> "
+				style = SelectedStyle
+				focus = len(lines)
+			}
+
+			line := style.Render(fmt.Sprintf("%s%s  [%s] %s",
+				cursor, task.TaskDate.Format("Jan 02"), formatHours(task.EstimatedHours), task.Description))
+			if len(task.SourceCommits) == 0 {
+				line += DimStyle.Render(" (manual)")
+			}
+			lines = append(lines, line)
+		}
+
+		visible := 0
+		if t.height > 0 {
+			// Leave room for the title, month, messages and help
+			visible = max(5, t.height-10)
+		}
+		b.WriteString(strings.Join(scrollWindow(lines, focus, visible), "\n"))
+		b.WriteString("\n\n")
+	}
+
+	help := "[a] Add  [e] Edit  [d] Delete  [←/→] Month  [q] Back"
+	b.WriteString(HelpStyle.Render(help))
+
+	return b.String()
+}
+
+func (t *Tasks) viewForm(b *strings.Builder) string {
+	if t.editing != nil {
+		b.WriteString("Edit task\n\n")
+	} else {
+		b.WriteString("Add task\n")
+		b.WriteString(DimStyle.Render("For work without commits, like meetings, reviews or support calls."))
+		b.WriteString("\n\n")
+	}
+
+	labels := []string{"Description", "Hours", "Date"}
+	for i, label := range labels {
+		b.WriteString(fmt.Sprintf("%-12s %s\n", label+":", t.inputs[i].View()))
+	}
+
+	project := ""
+	if len(t.projects) > 0 {
+		p := t.projects[t.projectCursor]
+		project = p.Name
+		if p.CompanyName != "" {
+			project += DimStyle.Render(fmt.Sprintf(" (%s)", p.CompanyName))
+		}
+	}
+	if t.focus == taskFieldProject {
+		project = SelectedStyle.Render("< ") + project + SelectedStyle.Render(" >")
+	}
+	b.WriteString(fmt.Sprintf("%-12s %s\n", "Project:", project))
+
+	if t.editing != nil && len(t.editing.SourceCommits) > 0 {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("\nDerived from %d commits.", len(t.editing.SourceCommits))))
+		b.WriteString("\n")
+	}
+
+	if t.formErr != nil {
+		b.WriteString("\n")
+		b.WriteString(ErrorStyle.Render(fmt.Sprintf("Error: %v", t.formErr)))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(HelpStyle.Render("[tab] Next field  [←/→] Change project  [enter] Save  [esc] Cancel"))
+	return b.String()
+}